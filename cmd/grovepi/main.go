@@ -4,8 +4,11 @@ import (
 	"flag"
 	"gobot-grovepi-platform/pkg/config"
 	"gobot-grovepi-platform/pkg/platform"
+	"log"
 	"os"
+	"os/signal"
 	"path"
+	"syscall"
 )
 
 var (
@@ -42,14 +45,29 @@ func main() {
 		panic(err)
 	}
 
-	p := platform.GetPlatform()
+	m := platform.GetMaster()
 	//TODO add services to work func and provide it as argument to Init
-	err = p.Init(conf.Platform)
+	err = m.Init(conf)
 	if err != nil {
 		panic(err)
 	}
-	err = p.Run()
+	go reloadOnHangup(m)
+
+	err = m.Run()
 	if err != nil {
 		panic(err)
 	}
 }
+
+// reloadOnHangup reloads device configuration on SIGHUP
+func reloadOnHangup(m *platform.Master) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	for range c {
+		if err := m.ReloadFromFile(); err != nil {
+			log.Println("Reload failed:", err)
+			continue
+		}
+		log.Println("Configuration reloaded")
+	}
+}
@@ -7,9 +7,12 @@ import (
 )
 
 type AppConfig struct {
-	Version  string           `yaml:"version"`
-	Services []*ServiceConfig `yaml:"services,omitempty"`
-	Platform *GrovePiConfig   `yaml:"platform"`
+	Version   string                          `yaml:"version"`
+	Services  []*ServiceConfig                `yaml:"services,omitempty"`
+	Platform  *GrovePiConfig                  `yaml:"platform,omitempty"`
+	Platforms []*GrovePiConfig                `yaml:"platforms,omitempty"`
+	Profiles  map[string]*SensorProfileConfig `yaml:"profiles,omitempty"`
+	file      string
 }
 
 type OptAppConfig func(ac *AppConfig)
@@ -44,7 +47,7 @@ func LoadFromFile(conf string) (*AppConfig, error) {
 		return nil, err
 	}
 
-	ac := &AppConfig{}
+	ac := &AppConfig{file: conf}
 
 	err = yaml.Unmarshal(bytes, ac)
 	if err != nil {
@@ -53,6 +56,28 @@ func LoadFromFile(conf string) (*AppConfig, error) {
 	return ac, nil
 }
 
+// File returns the name of the file the config was loaded from
+func (a *AppConfig) File() string { return a.file }
+
+// Save writes the config back to the file it was loaded from
+func (a *AppConfig) Save() error {
+	return a.SaveToFile(a.file)
+}
+
+// SaveToFile writes the config to the file
+func (a *AppConfig) SaveToFile(conf string) error {
+	if conf == "" {
+		return ErrorInvalidConfigFile
+	}
+
+	bytes, err := a.ToYaml()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(conf, bytes, 0644)
+}
+
 func (a *AppConfig) ToYaml() ([]byte, error) {
 
 	bytes, err := yaml.Marshal(a)
@@ -63,6 +88,20 @@ func (a *AppConfig) ToYaml() ([]byte, error) {
 	return bytes, nil
 }
 
+// AllPlatforms returns the single platform config followed by the named ones
+func (a *AppConfig) AllPlatforms() []*GrovePiConfig {
+	platforms := make([]*GrovePiConfig, 0, len(a.Platforms)+1)
+	if a.Platform != nil {
+		platforms = append(platforms, a.Platform)
+	}
+	for _, p := range a.Platforms {
+		if p != nil {
+			platforms = append(platforms, p)
+		}
+	}
+	return platforms
+}
+
 func WithServiceConfig(s interface{}) OptAppConfig {
 	return func(ac *AppConfig) {
 		if ac != nil {
@@ -82,3 +121,13 @@ func WithPlatformConfig(p interface{}) OptAppConfig {
 		}
 	}
 }
+
+func WithPlatformsConfig(p interface{}) OptAppConfig {
+	return func(ac *AppConfig) {
+		if ac != nil {
+			if platform, ok := p.(*GrovePiConfig); ok {
+				ac.Platforms = append(ac.Platforms, platform)
+			}
+		}
+	}
+}
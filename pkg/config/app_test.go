@@ -49,3 +49,17 @@ func TestLoadAppConfigFromFile(t *testing.T) {
 	}
 	fmt.Println(string(bytes))
 }
+
+func TestAllPlatforms(t *testing.T) {
+	ac := NewAppConfig(
+		WithPlatformsConfig(NewGrovePiConfig(WithGrovePiName("board1"), WithGrovePiAddress(0x04))),
+		WithPlatformsConfig(NewGrovePiConfig(WithGrovePiName("board2"), WithGrovePiAddress(0x05))))
+
+	platforms := ac.AllPlatforms()
+	if len(platforms) != 3 {
+		t.Fatalf("expected 3 platforms, got %d", len(platforms))
+	}
+	if platforms[1].Name != "board1" || platforms[2].Name != "board2" {
+		t.Errorf("unexpected platforms order: %s, %s", platforms[1].Name, platforms[2].Name)
+	}
+}
@@ -1,10 +1,10 @@
 package config
 
 type DeviceConfig struct {
-	Name       string                 `yaml:"name"`
-	Driver     string                 `yaml:"driver"`
-	Pin        string                 `yaml:"pin"`
-	Properties map[string]interface{} `yaml:"config,omitempty"`
+	Name       string                 `yaml:"name" json:"name"`
+	Driver     string                 `yaml:"driver" json:"driver"`
+	Pin        string                 `yaml:"pin" json:"pin"`
+	Properties map[string]interface{} `yaml:"config,omitempty" json:"config,omitempty"`
 }
 
 type OptDeviceConfig func(d *DeviceConfig)
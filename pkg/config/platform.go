@@ -1,9 +1,13 @@
 package config
 
 type GrovePiConfig struct {
-	Bus     int             `yaml:"bus,omitempty"`
-	Address int             `yaml:"address,omitempty"`
-	Devices []*DeviceConfig `yaml:"devices,omitempty"`
+	Name       string                  `yaml:"name,omitempty"`
+	Bus        int                     `yaml:"bus,omitempty"`
+	Address    int                     `yaml:"address,omitempty"`
+	Retry      *RetryConfig            `yaml:"retry,omitempty"`
+	Commands   map[string]*RetryConfig `yaml:"commands,omitempty"`
+	ResetAfter int                     `yaml:"resetAfter,omitempty"`
+	Devices    []*DeviceConfig         `yaml:"devices,omitempty"`
 }
 
 type OptGrovePiConfig func(c *GrovePiConfig)
@@ -28,6 +32,16 @@ func WithGrovePiDeviceConfig(d interface{}) OptGrovePiConfig {
 	}
 }
 
+func WithGrovePiName(n interface{}) OptGrovePiConfig {
+	return func(c *GrovePiConfig) {
+		if c != nil && n != nil {
+			if name, ok := n.(string); ok {
+				c.Name = name
+			}
+		}
+	}
+}
+
 func WithGrovePiBus(b interface{}) OptGrovePiConfig {
 	return func(c *GrovePiConfig) {
 		if c != nil && b != nil {
@@ -47,3 +61,36 @@ func WithGrovePiAddress(a interface{}) OptGrovePiConfig {
 		}
 	}
 }
+
+func WithGrovePiRetry(r interface{}) OptGrovePiConfig {
+	return func(c *GrovePiConfig) {
+		if c != nil && r != nil {
+			if retry, ok := r.(*RetryConfig); ok {
+				c.Retry = retry
+			}
+		}
+	}
+}
+
+func WithGrovePiCommandRetry(cmd string, r interface{}) OptGrovePiConfig {
+	return func(c *GrovePiConfig) {
+		if c != nil && cmd != "" && r != nil {
+			if retry, ok := r.(*RetryConfig); ok {
+				if c.Commands == nil {
+					c.Commands = map[string]*RetryConfig{}
+				}
+				c.Commands[cmd] = retry
+			}
+		}
+	}
+}
+
+func WithGrovePiResetAfter(n interface{}) OptGrovePiConfig {
+	return func(c *GrovePiConfig) {
+		if c != nil && n != nil {
+			if resetAfter, ok := n.(int); ok {
+				c.ResetAfter = resetAfter
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package config
+
+// SensorProfileConfig describes custom analog sensor profile, calibration has the format of the calibration
+// device property, unit overrides the calibration unit and levels map level names on their lowest values
+type SensorProfileConfig struct {
+	Calibration interface{}        `yaml:"calibration,omitempty" json:"calibration,omitempty"`
+	Unit        string             `yaml:"unit,omitempty" json:"unit,omitempty"`
+	Min         *float64           `yaml:"min,omitempty" json:"min,omitempty"`
+	Max         *float64           `yaml:"max,omitempty" json:"max,omitempty"`
+	Levels      map[string]float64 `yaml:"levels,omitempty" json:"levels,omitempty"`
+}
@@ -0,0 +1,9 @@
+package config
+
+// RetryConfig describes retry policy of GrovePi commands, durations are in time.ParseDuration format
+type RetryConfig struct {
+	Attempts   int    `yaml:"attempts,omitempty"`
+	Backoff    string `yaml:"backoff,omitempty"`
+	MaxBackoff string `yaml:"maxBackoff,omitempty"`
+	Timeout    string `yaml:"timeout,omitempty"`
+}
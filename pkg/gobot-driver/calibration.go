@@ -0,0 +1,132 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultThermistorBeta is the B constant of the Grove temperature sensor thermistor
+const DefaultThermistorBeta = 4275
+
+// ErrorInvalidCalibration is returned for calibration which can't convert values
+var ErrorInvalidCalibration = errors.New("invalid calibration")
+
+// CalibrationPoint maps the raw value on the value in engineering units
+type CalibrationPoint struct {
+	Raw   float64
+	Value float64
+}
+
+// Calibration converts raw ADC counts to engineering units
+type Calibration struct {
+	Unit    string
+	convert func(raw float64) float64
+}
+
+// NewLinearCalibration creates calibration converting raw value to raw*scale + offset
+func NewLinearCalibration(scale, offset float64, unit string) *Calibration {
+	return &Calibration{Unit: unit, convert: func(raw float64) float64 {
+		return raw*scale + offset
+	}}
+}
+
+// NewPolynomialCalibration creates calibration converting raw value by the polynomial,
+// coefficients start with the constant term
+func NewPolynomialCalibration(coefficients []float64, unit string) (*Calibration, error) {
+	if len(coefficients) == 0 {
+		return nil, fmt.Errorf("%w: no polynomial coefficients", ErrorInvalidCalibration)
+	}
+	c := append([]float64{}, coefficients...)
+	return &Calibration{Unit: unit, convert: func(raw float64) float64 {
+		value := 0.0
+		for i := len(c) - 1; i >= 0; i-- {
+			value = value*raw + c[i]
+		}
+		return value
+	}}, nil
+}
+
+// NewTableCalibration creates calibration interpolating linearly between the points,
+// values out of the table are clamped to its ends
+func NewTableCalibration(points []CalibrationPoint, unit string) (*Calibration, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("%w: table needs at least 2 points", ErrorInvalidCalibration)
+	}
+	table := append([]CalibrationPoint{}, points...)
+	sort.Slice(table, func(i, j int) bool { return table[i].Raw < table[j].Raw })
+	for i := 1; i < len(table); i++ {
+		if table[i].Raw == table[i-1].Raw {
+			return nil, fmt.Errorf("%w: duplicate raw value %v", ErrorInvalidCalibration, table[i].Raw)
+		}
+	}
+
+	return &Calibration{Unit: unit, convert: func(raw float64) float64 {
+		i := sort.Search(len(table), func(i int) bool { return table[i].Raw >= raw })
+		if i == 0 {
+			return table[0].Value
+		}
+		if i == len(table) {
+			return table[len(table)-1].Value
+		}
+		a, b := table[i-1], table[i]
+		return a.Value + (b.Value-a.Value)*(raw-a.Raw)/(b.Raw-a.Raw)
+	}}, nil
+}
+
+// NewThermistorCalibration creates calibration converting the raw value of NTC thermistor divider to °C,
+// the thermistor has the nominal resistance of the divider resistor at 25°C
+func NewThermistorCalibration(beta float64) (*Calibration, error) {
+	if beta <= 0 {
+		return nil, fmt.Errorf("%w: beta %v", ErrorInvalidCalibration, beta)
+	}
+	return &Calibration{Unit: "°C", convert: func(raw float64) float64 {
+		if raw <= 0 || raw >= 1023 {
+			return math.NaN()
+		}
+		ratio := 1023/raw - 1
+		return 1/(math.Log(ratio)/beta+1/298.15) - 273.15
+	}}, nil
+}
+
+// Convert returns the raw value in engineering units
+func (c *Calibration) Convert(raw float64) float64 {
+	return c.convert(raw)
+}
+
+// calibrationPresets are the calibrations of the Grove analog modules on 5V GrovePi, lux and dB are estimates
+var calibrationPresets = map[string]func() *Calibration{
+	"volts": func() *Calibration {
+		return NewLinearCalibration(5.0/1023, 0, "V")
+	},
+	"percent": func() *Calibration {
+		return NewLinearCalibration(100.0/1023, 0, "%")
+	},
+	"degrees": func() *Calibration {
+		// Grove rotary angle sensor turns by 300 degrees
+		return NewLinearCalibration(300.0/1023, 0, "°")
+	},
+	"lux": func() *Calibration {
+		c, _ := NewTableCalibration([]CalibrationPoint{
+			{0, 0}, {100, 0.2}, {200, 1}, {300, 3}, {400, 6}, {500, 10}, {600, 15},
+			{700, 35}, {800, 80}, {900, 200}, {1000, 800}, {1023, 1000},
+		}, "lx")
+		return c
+	},
+	"db": func() *Calibration {
+		c, _ := NewTableCalibration([]CalibrationPoint{
+			{0, 30}, {10, 45}, {50, 55}, {100, 60}, {300, 70}, {700, 80}, {1023, 90},
+		}, "dB")
+		return c
+	},
+}
+
+// PresetCalibration returns the named built-in calibration: volts, percent, degrees, lux or db
+func PresetCalibration(name string) (*Calibration, bool) {
+	preset, found := calibrationPresets[name]
+	if !found {
+		return nil, false
+	}
+	return preset(), true
+}
@@ -0,0 +1,63 @@
+package gobot_driver
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+func TestCalibrations(t *testing.T) {
+	poly, err := NewPolynomialCalibration([]float64{1, 2, 3}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := poly.Convert(2); v != 17 {
+		t.Errorf("expected 1 + 2*2 + 3*4 = 17, got %v", v)
+	}
+
+	table, err := NewTableCalibration([]CalibrationPoint{{1023, 100}, {0, 0}, {512, 20}}, "%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for raw, want := range map[float64]float64{-5: 0, 256: 10, 512: 20, 2000: 100} {
+		if v := table.Convert(raw); math.Abs(v-want) > 1e-9 {
+			t.Errorf("table(%v) = %v, want %v", raw, v, want)
+		}
+	}
+
+	if _, err := NewTableCalibration([]CalibrationPoint{{0, 0}}, ""); err == nil {
+		t.Error("single point table should fail")
+	}
+	if c, found := PresetCalibration("volts"); !found || c.Unit != "V" || c.Convert(1023) != 5 {
+		t.Error("unexpected volts preset")
+	}
+}
+
+func TestPipelinePublishesValue(t *testing.T) {
+	source := gobot.NewEventer()
+	source.AddEvent(aio.Data)
+	p := NewPipeline(NewLinearCalibration(0.5, 1, "V"))
+	p.Attach(source)
+	defer p.Detach()
+
+	values := make(chan Measurement, 1)
+	unsubscribe := subscribe(source, func(evt *gobot.Event) {
+		if evt.Name == Value {
+			values <- evt.Data.(Measurement)
+		}
+	})
+	defer unsubscribe()
+
+	source.Publish(aio.Data, 10)
+	select {
+	case m := <-values:
+		if m.Value != 6 || m.Raw != 10 || m.Unit != "V" {
+			t.Errorf("unexpected measurement %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("value not published")
+	}
+}
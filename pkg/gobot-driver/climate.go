@@ -0,0 +1,91 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+)
+
+// Temperature units
+const (
+	Celsius    = "C"
+	Fahrenheit = "F"
+	Kelvin     = "K"
+)
+
+// Climate is the reading of the temperature and humidity sensor with the derived metrics.
+// Temperatures are in Unit, humidity in %RH and absolute humidity in g/m³
+type Climate struct {
+	Temperature      float32 `json:"temperature"`
+	Humidity         float32 `json:"humidity"`
+	DewPoint         float32 `json:"dewPoint"`
+	HeatIndex        float32 `json:"heatIndex"`
+	AbsoluteHumidity float32 `json:"absoluteHumidity"`
+	Unit             string  `json:"unit"`
+}
+
+// NewClimate derives the metrics of the temperature in Celsius degrees and relative humidity,
+// temperatures of the result are converted to the unit. The metrics are undefined for humidity
+// out of 0..100 % excluding zero, such readings are reported as ErrorOutOfRange
+func NewClimate(celsius, humidity float64, unit string) (Climate, error) {
+	if _, err := ConvertTemperature(0, unit); err != nil {
+		return Climate{}, err
+	}
+	if !(humidity > 0 && humidity <= 100) || math.IsNaN(celsius) {
+		return Climate{}, fmt.Errorf("%w: temperature %v°C, humidity %v%%", ErrorOutOfRange, celsius, humidity)
+	}
+	convert := func(t float64) float32 {
+		v, _ := ConvertTemperature(t, unit)
+		return float32(v)
+	}
+	return Climate{
+		Temperature:      convert(celsius),
+		Humidity:         float32(humidity),
+		DewPoint:         convert(dewPoint(celsius, humidity)),
+		HeatIndex:        convert(heatIndex(celsius, humidity)),
+		AbsoluteHumidity: float32(absoluteHumidity(celsius, humidity)),
+		Unit:             unit,
+	}, nil
+}
+
+// ConvertTemperature converts the temperature in Celsius degrees to the unit
+func ConvertTemperature(celsius float64, unit string) (float64, error) {
+	switch unit {
+	case Celsius:
+		return celsius, nil
+	case Fahrenheit:
+		return celsius*9/5 + 32, nil
+	case Kelvin:
+		return celsius + 273.15, nil
+	}
+	return 0, fmt.Errorf("%w: temperature unit %q", ErrorOutOfRange, unit)
+}
+
+// dewPoint returns the dew point in Celsius degrees by the Magnus formula
+func dewPoint(celsius, humidity float64) float64 {
+	const b, c = 17.62, 243.12
+	gamma := math.Log(humidity/100) + b*celsius/(c+celsius)
+	return c * gamma / (b - gamma)
+}
+
+// heatIndex returns the apparent temperature in Celsius degrees by the NOAA formula,
+// below 80°F it's the simple Steadman approximation
+func heatIndex(celsius, humidity float64) float64 {
+	t := celsius*9/5 + 32
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + humidity*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*humidity -
+			0.22475541*t*humidity - 0.00683783*t*t - 0.05481717*humidity*humidity +
+			0.00122874*t*t*humidity + 0.00085282*t*humidity*humidity - 0.00000199*t*t*humidity*humidity
+		if humidity < 13 && t >= 80 && t <= 112 {
+			hi -= (13 - humidity) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		} else if humidity > 85 && t >= 80 && t <= 87 {
+			hi += (humidity - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return (hi - 32) * 5 / 9
+}
+
+// absoluteHumidity returns the water vapour density in g/m³
+func absoluteHumidity(celsius, humidity float64) float64 {
+	return 6.112 * math.Exp(17.67*celsius/(celsius+243.5)) * humidity * 2.1674 / (273.15 + celsius)
+}
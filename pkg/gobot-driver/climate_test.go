@@ -0,0 +1,118 @@
+package gobot_driver
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+func TestNewClimate(t *testing.T) {
+	c, err := NewClimate(25, 60, Celsius)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(c.DewPoint)-16.7) > 0.1 {
+		t.Errorf("expected dew point 16.7°C, got %v", c.DewPoint)
+	}
+	if math.Abs(float64(c.AbsoluteHumidity)-13.8) > 0.1 {
+		t.Errorf("expected absolute humidity 13.8 g/m³, got %v", c.AbsoluteHumidity)
+	}
+
+	c, _ = NewClimate(32, 70, Fahrenheit)
+	if c.Temperature != 89.6 || math.Abs(float64(c.HeatIndex)-105) > 1 {
+		t.Errorf("expected 89.6°F feeling like 105°F, got %+v", c)
+	}
+
+	c, _ = NewClimate(0, 50, Kelvin)
+	if c.Temperature != 273.15 || c.HeatIndex >= c.Temperature+1 {
+		t.Errorf("unexpected cold climate %+v", c)
+	}
+
+	if _, err := NewClimate(20, 50, "R"); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected unknown unit error, got %v", err)
+	}
+}
+
+func TestNewClimateUndefined(t *testing.T) {
+	for _, humidity := range []float64{0, -1, math.NaN(), 101} {
+		if _, err := NewClimate(20, humidity, Celsius); !errors.Is(err, ErrorOutOfRange) {
+			t.Errorf("%v %%: expected out of range error, got %v", humidity, err)
+		}
+	}
+}
+
+func TestGroveTemperatureAndHumidityReadAll(t *testing.T) {
+	d := NewGroveTemperatureAndHumidityDriver(nil, "D7")
+	readAll := func() map[string]interface{} {
+		t.Helper()
+		data, err := json.Marshal(d.Command("ReadAll")(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply := map[string]interface{}{}
+		if err := json.Unmarshal(data, &reply); err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+
+	if _, err := d.Climate(); !errors.Is(err, ErrorNoReading) {
+		t.Errorf("expected no reading error, got %v", err)
+	}
+	if reply := readAll(); reply["error"] == nil || reply["dewPoint"] != nil {
+		t.Errorf("derived metrics should be omitted before the first reading, got %v", reply)
+	}
+
+	d.temp, d.humid, d.valid = 20, 0, true
+	if reply := readAll(); reply["error"] == nil || reply["humidity"] != 0.0 {
+		t.Errorf("derived metrics should be omitted at 0 %% humidity, got %v", reply)
+	}
+
+	d.humid = 50
+	if reply := readAll(); reply["error"] != nil || reply["dewPoint"] == nil {
+		t.Errorf("expected derived metrics, got %v", reply)
+	}
+}
+
+func TestGroveTemperatureAndHumidityPublishEpsilonInUnit(t *testing.T) {
+	d := NewGroveTemperatureAndHumidityDriver(nil, "D7")
+	if err := d.SetUnit(Fahrenheit); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetPublishPolicy(PublishPolicy{Mode: PublishOnChange, Epsilon: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	temperatures := make(chan float32, 10)
+	unsubscribe := subscribe(d, func(evt *gobot.Event) {
+		if evt.Name == Temperature {
+			temperatures <- evt.Data.(float32)
+		}
+	})
+	defer unsubscribe()
+
+	// 0.2°C is 0.36°F and 0.4°C is 0.72°F
+	now := time.Now()
+	d.sample(20, 50, now)
+	d.sample(20.2, 50, now)
+	d.sample(20.4, 50, now)
+
+	for _, expected := range []float64{68, 68.72} {
+		select {
+		case got := <-temperatures:
+			if math.Abs(float64(got)-expected) > 0.01 {
+				t.Errorf("expected %v°F, got %v", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v°F to be published", expected)
+		}
+	}
+	select {
+	case got := <-temperatures:
+		t.Errorf("unexpected temperature %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
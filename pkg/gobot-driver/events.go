@@ -0,0 +1,79 @@
+package gobot_driver
+
+import (
+	"sync"
+
+	"gobot.io/x/gobot"
+)
+
+// subscribe calls f for every event published by the eventer until the returned function is called.
+// The subscription keeps draining events while unsubscribing so the eventer is never blocked.
+// Events are queued for f in a separate goroutine, so f can publish on the same eventer: gobot's eventer
+// holds its lock while sending to the subscribers, so a subscriber blocked on Publish would deadlock it.
+// The queue isn't bounded, it only grows while f is slower than the eventer publishes.
+// Unsubscribe drops the queued events and waits until f returns, so it must not be called from f
+// or while holding a lock f takes
+func subscribe(e gobot.Eventer, f func(evt *gobot.Event)) (unsubscribe func()) {
+	events := e.Subscribe()
+	done := make(chan struct{})
+	queued := make(chan *gobot.Event)
+	finished := make(chan struct{})
+
+	go func() {
+		for evt := range queued {
+			f(evt)
+		}
+		close(finished)
+	}()
+
+	go func() {
+		var pending []*gobot.Event
+		for {
+			// unsubscribing takes precedence over the queued events
+			select {
+			case <-done:
+				close(queued)
+				drain(e, events)
+				return
+			default:
+			}
+
+			var next chan<- *gobot.Event
+			var evt *gobot.Event
+			if len(pending) > 0 {
+				next, evt = queued, pending[0]
+			}
+
+			select {
+			case received := <-events:
+				pending = append(pending, received)
+			case next <- evt:
+				pending[0] = nil
+				pending = pending[1:]
+			case <-done:
+			}
+		}
+	}()
+
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() { close(done) })
+		<-finished
+	}
+}
+
+// drain unsubscribes the events channel, the channel is drained meanwhile so the eventer isn't blocked
+func drain(e gobot.Eventer, events chan *gobot.Event) {
+	unsubscribed := make(chan struct{})
+	go func() {
+		e.Unsubscribe(events)
+		close(unsubscribed)
+	}()
+	for {
+		select {
+		case <-events:
+		case <-unsubscribed:
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package gobot_driver
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+func TestSubscribeRepublish(t *testing.T) {
+	const samples = 200
+
+	source := gobot.NewEventer()
+	p := NewPipeline(nil)
+	p.Attach(source)
+	defer p.Detach()
+
+	values := make(chan float64, samples)
+	unsubscribe := subscribe(source, func(evt *gobot.Event) {
+		if m, ok := evt.Data.(Measurement); ok && evt.Name == Value {
+			values <- m.Value
+		}
+	})
+	defer unsubscribe()
+
+	// the burst fills the eventer buffers while the pipeline republishes on the same eventer
+	go func() {
+		for i := 0; i < samples; i++ {
+			source.Publish(aio.Data, i)
+		}
+	}()
+
+	for i := 0; i < samples; i++ {
+		select {
+		case v := <-values:
+			if v != float64(i) {
+				t.Fatalf("expected value %d, got %v", i, v)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("eventer blocked after %d of %d values", i, samples)
+		}
+	}
+}
+
+func TestUnsubscribeWaitsForHandler(t *testing.T) {
+	source := gobot.NewEventer()
+	var calls, after int32
+	unsubscribed := int32(0)
+	started := make(chan struct{}, 1)
+	unsubscribe := subscribe(source, func(evt *gobot.Event) {
+		if atomic.LoadInt32(&unsubscribed) == 1 {
+			atomic.AddInt32(&after, 1)
+		}
+		atomic.AddInt32(&calls, 1)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		time.Sleep(time.Millisecond)
+	})
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			source.Publish(aio.Data, i)
+		}
+	}()
+	<-started
+	unsubscribe()
+	atomic.StoreInt32(&unsubscribed, 1)
+	n := atomic.LoadInt32(&calls)
+
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&after) != 0 || atomic.LoadInt32(&calls) != n {
+		t.Errorf("handler called %d times after unsubscribe returned", atomic.LoadInt32(&calls)-n)
+	}
+	if n == 100 {
+		t.Error("queued events should be dropped on unsubscribe")
+	}
+}
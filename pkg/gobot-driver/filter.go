@@ -0,0 +1,261 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Filter processes the sensor values before they are published
+type Filter interface {
+	// Apply returns the filtered value, ok is false when the value shouldn't be published
+	Apply(value float64) (filtered float64, ok bool)
+	// Reset forgets the previous values
+	Reset()
+}
+
+// FilterChain applies the filters in order, the value dropped by a filter doesn't reach the next ones
+type FilterChain []Filter
+
+// Apply returns the value filtered by all filters, ok is false when any filter dropped it
+func (c FilterChain) Apply(value float64) (float64, bool) {
+	for _, f := range c {
+		var ok bool
+		if value, ok = f.Apply(value); !ok {
+			return 0, false
+		}
+	}
+	return value, true
+}
+
+// Reset resets all filters
+func (c FilterChain) Reset() {
+	for _, f := range c {
+		f.Reset()
+	}
+}
+
+// window keeps the last values
+type window struct {
+	size   int
+	values []float64
+}
+
+func (w *window) push(value float64) {
+	w.values = append(w.values, value)
+	if len(w.values) > w.size {
+		w.values = w.values[1:]
+	}
+}
+
+func (w *window) median() float64 {
+	sorted := append([]float64{}, w.values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// MovingAverageFilter averages the last values
+type MovingAverageFilter struct {
+	window
+}
+
+// NewMovingAverageFilter creates filter averaging the last size values
+func NewMovingAverageFilter(size int) (*MovingAverageFilter, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: moving average size %d", ErrorOutOfRange, size)
+	}
+	return &MovingAverageFilter{window{size: size}}, nil
+}
+
+// Apply implements the Filter interface
+func (f *MovingAverageFilter) Apply(value float64) (float64, bool) {
+	f.push(value)
+	sum := 0.0
+	for _, v := range f.values {
+		sum += v
+	}
+	return sum / float64(len(f.values)), true
+}
+
+// Reset implements the Filter interface
+func (f *MovingAverageFilter) Reset() { f.values = nil }
+
+// MedianFilter returns median of the last values
+type MedianFilter struct {
+	window
+}
+
+// NewMedianFilter creates filter returning median of the last size values
+func NewMedianFilter(size int) (*MedianFilter, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: median size %d", ErrorOutOfRange, size)
+	}
+	return &MedianFilter{window{size: size}}, nil
+}
+
+// Apply implements the Filter interface
+func (f *MedianFilter) Apply(value float64) (float64, bool) {
+	f.push(value)
+	return f.median(), true
+}
+
+// Reset implements the Filter interface
+func (f *MedianFilter) Reset() { f.values = nil }
+
+// ExponentialFilter smooths the values exponentially, higher alpha follows the changes faster
+type ExponentialFilter struct {
+	alpha   float64
+	value   float64
+	started bool
+}
+
+// NewExponentialFilter creates exponential smoothing filter, alpha is from 0 to 1
+func NewExponentialFilter(alpha float64) (*ExponentialFilter, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("%w: exponential smoothing alpha %v", ErrorOutOfRange, alpha)
+	}
+	return &ExponentialFilter{alpha: alpha}, nil
+}
+
+// Apply implements the Filter interface
+func (f *ExponentialFilter) Apply(value float64) (float64, bool) {
+	if !f.started {
+		f.value, f.started = value, true
+	} else {
+		f.value += f.alpha * (value - f.value)
+	}
+	return f.value, true
+}
+
+// Reset implements the Filter interface
+func (f *ExponentialFilter) Reset() { f.started = false }
+
+// DeadbandFilter drops the values which differ from the last passed one less than the band
+type DeadbandFilter struct {
+	band    float64
+	last    float64
+	started bool
+}
+
+// NewDeadbandFilter creates deadband filter
+func NewDeadbandFilter(band float64) (*DeadbandFilter, error) {
+	if band < 0 {
+		return nil, fmt.Errorf("%w: deadband %v", ErrorOutOfRange, band)
+	}
+	return &DeadbandFilter{band: band}, nil
+}
+
+// Apply implements the Filter interface
+func (f *DeadbandFilter) Apply(value float64) (float64, bool) {
+	if f.started && math.Abs(value-f.last) < f.band {
+		return 0, false
+	}
+	f.last, f.started = value, true
+	return value, true
+}
+
+// Reset implements the Filter interface
+func (f *DeadbandFilter) Reset() { f.started = false }
+
+// RateLimitFilter passes at most one value per interval
+type RateLimitFilter struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// NewRateLimitFilter creates rate limiting filter
+func NewRateLimitFilter(interval time.Duration) (*RateLimitFilter, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: rate limit interval %v", ErrorOutOfRange, interval)
+	}
+	return &RateLimitFilter{interval: interval}, nil
+}
+
+// Apply implements the Filter interface
+func (f *RateLimitFilter) Apply(value float64) (float64, bool) {
+	now := time.Now()
+	if !f.last.IsZero() && now.Sub(f.last) < f.interval {
+		return 0, false
+	}
+	f.last = now
+	return value, true
+}
+
+// Reset implements the Filter interface
+func (f *RateLimitFilter) Reset() { f.last = time.Time{} }
+
+// OutlierFilter drops the values which deviate from the median of the recent accepted values more than the limit.
+// After size rejected values in a row the value is accepted as a real change
+type OutlierFilter struct {
+	window
+	maxDeviation float64
+	rejected     int
+}
+
+// NewOutlierFilter creates outlier rejection filter comparing the values with the median of size recent values
+func NewOutlierFilter(maxDeviation float64, size int) (*OutlierFilter, error) {
+	if maxDeviation <= 0 || size < 1 {
+		return nil, fmt.Errorf("%w: outlier deviation %v of %d values", ErrorOutOfRange, maxDeviation, size)
+	}
+	return &OutlierFilter{window: window{size: size}, maxDeviation: maxDeviation}, nil
+}
+
+// Apply implements the Filter interface
+func (f *OutlierFilter) Apply(value float64) (float64, bool) {
+	if len(f.values) > 0 && math.Abs(value-f.median()) > f.maxDeviation && f.rejected < f.size {
+		f.rejected++
+		return 0, false
+	}
+	if f.rejected >= f.size {
+		f.values = nil
+	}
+	f.rejected = 0
+	f.push(value)
+	return value, true
+}
+
+// Reset implements the Filter interface
+func (f *OutlierFilter) Reset() {
+	f.values = nil
+	f.rejected = 0
+}
+
+// FilteredAnalogReader filters the values read from the analog pin, it is used with the gobot analog drivers
+// which publish on change, so the dropped value repeats the last published one
+type FilteredAnalogReader struct {
+	*GrovePiDriver
+	mutex sync.Mutex
+	chain FilterChain
+	last  int
+	valid bool
+}
+
+// NewFilteredAnalogReader creates analog reader filtering the values of the single pin
+func NewFilteredAnalogReader(gp *GrovePiDriver, chain FilterChain) *FilteredAnalogReader {
+	return &FilteredAnalogReader{GrovePiDriver: gp, chain: chain}
+}
+
+// AnalogRead returns the filtered value of the pin, implementing the AnalogReader interface
+func (r *FilteredAnalogReader) AnalogRead(pin string) (int, error) {
+	raw, err := r.GrovePiDriver.AnalogRead(pin)
+	if err != nil {
+		return 0, err
+	}
+
+	// the gobot drivers read the pin from the polling goroutine and their Read command
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if value, ok := r.chain.Apply(float64(raw)); ok {
+		r.last, r.valid = int(math.Round(value)), true
+	} else if !r.valid {
+		return raw, nil
+	}
+	return r.last, nil
+}
@@ -0,0 +1,68 @@
+package gobot_driver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterChain(t *testing.T) {
+	median, _ := NewMedianFilter(3)
+	average, _ := NewMovingAverageFilter(2)
+	deadband, _ := NewDeadbandFilter(1)
+	chain := FilterChain{median, average, deadband}
+
+	type result struct {
+		value float64
+		ok    bool
+	}
+	var got []result
+	for _, v := range []float64{10, 100, 10, 10, 10.4} {
+		value, ok := chain.Apply(v)
+		got = append(got, result{value, ok})
+	}
+	// the spike of 100 is removed by the median, the small change by the deadband
+	want := []result{{10, true}, {32.5, true}, {0, false}, {10, true}, {0, false}}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("value %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var empty FilterChain
+	if v, ok := empty.Apply(7); v != 7 || !ok {
+		t.Error("empty chain should pass values through")
+	}
+}
+
+func TestOutlierFilter(t *testing.T) {
+	f, err := NewOutlierFilter(5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range []struct {
+		value float64
+		ok    bool
+	}{{20, true}, {21, true}, {90, false}, {20, true}, {50, false}, {50, false}, {50, true}, {51, true}} {
+		if _, ok := f.Apply(c.value); ok != c.ok {
+			t.Errorf("value %d (%v): expected ok %v", i, c.value, c.ok)
+		}
+	}
+
+	if _, err := NewExponentialFilter(1.5); err == nil {
+		t.Error("alpha out of 0..1 should fail")
+	}
+}
+
+func TestRateLimitFilter(t *testing.T) {
+	f, _ := NewRateLimitFilter(20 * time.Millisecond)
+	if _, ok := f.Apply(1); !ok {
+		t.Error("first value should pass")
+	}
+	if _, ok := f.Apply(2); ok {
+		t.Error("value within interval should be dropped")
+	}
+	time.Sleep(25 * time.Millisecond)
+	if _, ok := f.Apply(3); !ok {
+		t.Error("value after interval should pass")
+	}
+}
@@ -0,0 +1,212 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+// Gesture events published by Gestures on the input device, their data is the number of clicks or repeats
+const (
+	Click       = "click"
+	DoubleClick = "double-click"
+	LongPress   = "long-press"
+	HoldRepeat  = "hold-repeat"
+)
+
+// GestureTimings configures the gesture recognition
+//
+//	DoubleClick	- the longest pause between two clicks of a double click, zero publishes clicks right away
+//	LongPress	- how long the input is held before long press
+//	Repeat		- interval of hold repeats after long press, zero disables them
+type GestureTimings struct {
+	DoubleClick time.Duration `json:"doubleClick"`
+	LongPress   time.Duration `json:"longPress"`
+	Repeat      time.Duration `json:"repeat"`
+}
+
+// DefaultGestureTimings are the timings of the usual push button
+var DefaultGestureTimings = GestureTimings{
+	DoubleClick: 300 * time.Millisecond,
+	LongPress:   800 * time.Millisecond,
+	Repeat:      200 * time.Millisecond,
+}
+
+// Gestures recognizes clicks, double clicks, long presses and hold repeats of a digital input
+// from its press and release events and publishes them on the input device
+type Gestures struct {
+	mutex       sync.Mutex
+	timings     GestureTimings
+	press       string
+	release     string
+	source      gobot.Eventer
+	unsubscribe func()
+	pressed     bool
+	held        bool
+	clicks      int
+	repeats     int
+	timer       *time.Timer
+	seq         int
+}
+
+// NewGestures creates gesture recognizer of the input publishing press and release events,
+// empty event names are gpio.ButtonPush and gpio.ButtonRelease
+func NewGestures(timings GestureTimings, press, release string) (*Gestures, error) {
+	if timings.DoubleClick < 0 || timings.LongPress <= 0 || timings.Repeat < 0 {
+		return nil, fmt.Errorf("%w: gesture timings %+v", ErrorOutOfRange, timings)
+	}
+	if press == "" {
+		press = gpio.ButtonPush
+	}
+	if release == "" {
+		release = gpio.ButtonRelease
+	}
+	return &Gestures{timings: timings, press: press, release: release}, nil
+}
+
+// Attach starts recognizing the source events, the recognizer can be attached to a single source
+func (g *Gestures) Attach(source gobot.Eventer) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.unsubscribe != nil {
+		return
+	}
+	source.AddEvent(Click)
+	source.AddEvent(DoubleClick)
+	source.AddEvent(LongPress)
+	source.AddEvent(HoldRepeat)
+	g.source = source
+	g.unsubscribe = subscribe(source, func(evt *gobot.Event) {
+		switch evt.Name {
+		case g.press:
+			g.pressInput()
+		case g.release:
+			g.releaseInput()
+		}
+	})
+}
+
+// Detach stops recognizing the source events
+func (g *Gestures) Detach() {
+	g.mutex.Lock()
+	unsubscribe := g.unsubscribe
+	g.unsubscribe = nil
+	g.mutex.Unlock()
+
+	// the input events are recognized under the mutex
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.reset()
+}
+
+// Timings returns the gesture timings
+func (g *Gestures) Timings() GestureTimings { return g.timings }
+
+func (g *Gestures) pressInput() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.pressed {
+		return
+	}
+	g.pressed, g.held, g.repeats = true, false, 0
+	g.schedule(g.timings.LongPress, g.hold)
+}
+
+func (g *Gestures) releaseInput() {
+	g.mutex.Lock()
+	if !g.pressed {
+		g.mutex.Unlock()
+		return
+	}
+	g.pressed = false
+	g.stop()
+	if g.held {
+		g.held = false
+		g.mutex.Unlock()
+		return
+	}
+
+	g.clicks++
+	switch {
+	case g.clicks == 2:
+		g.clicks = 0
+		g.mutex.Unlock()
+		g.source.Publish(DoubleClick, 2)
+	case g.timings.DoubleClick == 0:
+		g.clicks = 0
+		g.mutex.Unlock()
+		g.source.Publish(Click, 1)
+	default:
+		g.schedule(g.timings.DoubleClick, g.click)
+		g.mutex.Unlock()
+	}
+}
+
+// click publishes the single click when no second click came in time
+func (g *Gestures) click(seq int) {
+	g.mutex.Lock()
+	if seq != g.seq || g.pressed || g.clicks != 1 {
+		g.mutex.Unlock()
+		return
+	}
+	g.clicks = 0
+	g.timer = nil
+	g.mutex.Unlock()
+
+	g.source.Publish(Click, 1)
+}
+
+// hold publishes the long press and the following repeats while the input is held
+func (g *Gestures) hold(seq int) {
+	g.mutex.Lock()
+	if seq != g.seq || !g.pressed {
+		g.mutex.Unlock()
+		return
+	}
+	g.clicks = 0
+	g.timer = nil
+	name, data := LongPress, 1
+	if g.held {
+		g.repeats++
+		name, data = HoldRepeat, g.repeats
+	}
+	g.held = true
+	if g.timings.Repeat > 0 {
+		g.schedule(g.timings.Repeat, g.hold)
+	}
+	g.mutex.Unlock()
+
+	g.source.Publish(name, data)
+}
+
+// schedule replaces the pending timer, the caller must hold the mutex
+func (g *Gestures) schedule(d time.Duration, f func(seq int)) {
+	g.stop()
+	seq := g.seq
+	g.timer = time.AfterFunc(d, func() { f(seq) })
+}
+
+// stop cancels the pending timer, the caller must hold the mutex
+func (g *Gestures) stop() {
+	g.seq++
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+}
+
+// reset forgets the input state, the caller must hold the mutex
+func (g *Gestures) reset() {
+	g.stop()
+	g.pressed, g.held, g.clicks, g.repeats = false, false, 0, 0
+}
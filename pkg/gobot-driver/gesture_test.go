@@ -0,0 +1,68 @@
+package gobot_driver
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+func TestGestures(t *testing.T) {
+	g, err := NewGestures(GestureTimings{
+		DoubleClick: 40 * time.Millisecond,
+		LongPress:   60 * time.Millisecond,
+		Repeat:      50 * time.Millisecond,
+	}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := gobot.NewEventer()
+	source.AddEvent(gpio.ButtonPush)
+	source.AddEvent(gpio.ButtonRelease)
+	g.Attach(source)
+	defer g.Detach()
+
+	events := make(chan string, 20)
+	unsubscribe := subscribe(source, func(evt *gobot.Event) {
+		switch evt.Name {
+		case Click, DoubleClick, LongPress, HoldRepeat:
+			events <- evt.Name
+		}
+	})
+	defer unsubscribe()
+
+	expect := func(want ...string) {
+		t.Helper()
+		for _, name := range want {
+			select {
+			case got := <-events:
+				if got != name {
+					t.Errorf("expected %s, got %s", name, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("%s not published", name)
+			}
+		}
+		select {
+		case got := <-events:
+			t.Errorf("unexpected %s", got)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	g.pressInput()
+	g.releaseInput()
+	expect(Click)
+
+	g.pressInput()
+	g.releaseInput()
+	g.pressInput()
+	g.releaseInput()
+	expect(DoubleClick)
+
+	g.pressInput()
+	time.Sleep(135 * time.Millisecond)
+	g.releaseInput()
+	expect(LongPress, HoldRepeat)
+}
@@ -0,0 +1,284 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+// Actuator events
+const (
+	ActuatorOn      = "on"
+	ActuatorOff     = "off"
+	ActuatorAutoOff = "auto-off"
+)
+
+var (
+	// ErrorInterlocked is returned when another actuator of the interlock group is on
+	ErrorInterlocked = errors.New("interlocked by another actuator")
+	// ErrorMinTime is returned when the actuator is switched before its minimum on or off time
+	ErrorMinTime = errors.New("minimum on/off time not elapsed")
+)
+
+// Interlock is a group of actuators of which at most one may be on
+type Interlock struct {
+	name   string
+	mutex  sync.Mutex
+	holder *actuator
+}
+
+// NewInterlock creates new interlock group
+func NewInterlock(name string) *Interlock {
+	return &Interlock{name: name}
+}
+
+// Name returns the name of the group
+func (i *Interlock) Name() string { return i.name }
+
+func (i *Interlock) acquire(a *actuator) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.holder != nil && i.holder != a {
+		return fmt.Errorf("%w: %s of group %s is on", ErrorInterlocked, i.holder.name, i.name)
+	}
+	i.holder = a
+	return nil
+}
+
+func (i *Interlock) release(a *actuator) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.holder == a {
+		i.holder = nil
+	}
+}
+
+// actuator is the switching logic shared by the relay and MOSFET drivers.
+// Level 0 is off, any other level is on
+type actuator struct {
+	name      string
+	pin       string
+	grovepi   *GrovePiDriver
+	write     func(level byte) error
+	mutex     *sync.Mutex
+	level     byte
+	initial   byte
+	failSafe  byte
+	minOn     time.Duration
+	minOff    time.Duration
+	maxOn     time.Duration
+	changed   time.Time
+	autoOff   *time.Timer
+	seq       int
+	group     string
+	interlock *Interlock
+	gobot.Eventer
+	gobot.Commander
+}
+
+func newActuator(name string, gp *GrovePiDriver, pin string, write func(level byte) error) *actuator {
+	a := &actuator{
+		name:      gobot.DefaultName(name),
+		pin:       pin,
+		grovepi:   gp,
+		write:     write,
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	a.AddEvent(ActuatorOn)
+	a.AddEvent(ActuatorOff)
+	a.AddEvent(ActuatorAutoOff)
+	a.AddEvent(gpio.Error)
+
+	a.AddCommand("Off", func(params map[string]interface{}) interface{} {
+		return a.Off()
+	})
+
+	return a
+}
+
+// Name returns the Name for the Driver
+func (a *actuator) Name() string { return a.name }
+
+// SetName sets the Name for the Driver
+func (a *actuator) SetName(n string) { a.name = n }
+
+// Connection returns the connection for the Driver
+func (a *actuator) Connection() gobot.Connection {
+	return a.grovepi.Connection()
+}
+
+// Pin returns the pin of the actuator
+func (a *actuator) Pin() string { return a.pin }
+
+// Start switches the actuator to its default state
+func (a *actuator) Start() (err error) {
+	return a.set(a.initial, true)
+}
+
+// Halt switches the actuator to its fail-safe state
+func (a *actuator) Halt() (err error) {
+	a.mutex.Lock()
+	a.stopAutoOff()
+	a.mutex.Unlock()
+
+	return a.set(a.failSafe, true)
+}
+
+// SetTimes sets minimum on and off times and the maximal on duration after which the actuator is switched off,
+// zero disables the limit
+func (a *actuator) SetTimes(minOn, minOff, maxOn time.Duration) error {
+	if minOn < 0 || minOff < 0 || maxOn < 0 || (maxOn > 0 && maxOn < minOn) {
+		return fmt.Errorf("%w: times %v, %v, %v", ErrorOutOfRange, minOn, minOff, maxOn)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.minOn, a.minOff, a.maxOn = minOn, minOff, maxOn
+	return nil
+}
+
+// InterlockGroup returns the name of the interlock group, empty when the actuator isn't interlocked
+func (a *actuator) InterlockGroup() string { return a.group }
+
+// SetInterlockGroup sets the name of the interlock group, the group itself is set by SetInterlock
+func (a *actuator) SetInterlockGroup(group string) { a.group = group }
+
+// Interlock returns the interlock group
+func (a *actuator) Interlock() *Interlock {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.interlock
+}
+
+// SetInterlock joins the interlock group, nil leaves it
+func (a *actuator) SetInterlock(i *Interlock) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.level > 0 && i != nil {
+		if err := i.acquire(a); err != nil {
+			return err
+		}
+	}
+	if a.interlock != nil && a.interlock != i {
+		a.interlock.release(a)
+	}
+	a.interlock = i
+	return nil
+}
+
+// IsOn returns true when the actuator is on
+func (a *actuator) IsOn() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.level > 0
+}
+
+// Off switches the actuator off
+func (a *actuator) Off() error {
+	return a.set(0, false)
+}
+
+// set writes the level, forced change ignores the minimum on and off times
+func (a *actuator) set(level byte, force bool) error {
+	a.mutex.Lock()
+	wasOn, err := a.setLocked(level, force)
+	a.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	a.publishChange(level, wasOn)
+	return nil
+}
+
+// setLocked writes the level and returns the previous state, the caller must hold the mutex
+func (a *actuator) setLocked(level byte, force bool) (wasOn bool, err error) {
+	on, wasOn := level > 0, a.level > 0
+	if on != wasOn && !force {
+		elapsed := time.Since(a.changed)
+		if on && elapsed < a.minOff {
+			return wasOn, fmt.Errorf("%w: %s is off for %v of %v", ErrorMinTime, a.name, elapsed.Round(time.Millisecond), a.minOff)
+		}
+		if !on && elapsed < a.minOn {
+			return wasOn, fmt.Errorf("%w: %s is on for %v of %v", ErrorMinTime, a.name, elapsed.Round(time.Millisecond), a.minOn)
+		}
+	}
+	if on && !wasOn && a.interlock != nil {
+		if err := a.interlock.acquire(a); err != nil {
+			return wasOn, err
+		}
+	}
+
+	if err := a.write(level); err != nil {
+		if on && !wasOn && a.interlock != nil {
+			a.interlock.release(a)
+		}
+		return wasOn, err
+	}
+
+	a.level = level
+	if on != wasOn {
+		a.changed = time.Now()
+		a.stopAutoOff()
+		if on && a.maxOn > 0 {
+			seq := a.seq
+			a.autoOff = time.AfterFunc(a.maxOn, func() { a.expire(seq) })
+		}
+		if !on && a.interlock != nil {
+			a.interlock.release(a)
+		}
+	}
+	return wasOn, nil
+}
+
+// publishChange publishes the on and off events when the state changed
+func (a *actuator) publishChange(level byte, wasOn bool) {
+	if on := level > 0; on && !wasOn {
+		a.Publish(ActuatorOn, level)
+	} else if !on && wasOn {
+		a.Publish(ActuatorOff, level)
+	}
+}
+
+// stopAutoOff cancels the pending switch off, a timer which already fired is ignored by its seq.
+// The caller must hold the mutex
+func (a *actuator) stopAutoOff() {
+	a.seq++
+	if a.autoOff != nil {
+		a.autoOff.Stop()
+		a.autoOff = nil
+	}
+}
+
+// expire switches the actuator off after the maximal on duration unless it was switched since the timer started
+func (a *actuator) expire(seq int) {
+	a.mutex.Lock()
+	if seq != a.seq {
+		a.mutex.Unlock()
+		return
+	}
+	a.autoOff = nil
+	wasOn, err := a.setLocked(0, true)
+	maxOn := a.maxOn
+	a.mutex.Unlock()
+
+	if err != nil {
+		a.Publish(gpio.Error, err)
+		return
+	}
+	a.publishChange(0, wasOn)
+	a.Publish(ActuatorAutoOff, maxOn)
+}
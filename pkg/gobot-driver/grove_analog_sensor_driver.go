@@ -0,0 +1,158 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// LevelChange is the event of the sensor level change, its data is the level name
+const LevelChange = "level"
+
+// GroveAnalogSensorDriver represents an analog Grove module described by a sensor profile.
+// It publishes the raw aio.Data, the converted Value and the LevelChange events
+type GroveAnalogSensorDriver struct {
+	name     string
+	halt     chan bool
+	pin      string
+	interval time.Duration
+	grovepi  *GrovePiDriver
+	profile  *SensorProfile
+	filters  FilterChain
+	mutex    *sync.Mutex
+	raw      int
+	value    float64
+	level    string
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveAnalogSensorDriver creates new instance of GroveAnalogSensorDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the sensor is attached to
+//   pin string - analog pin of the sensor
+//   profile SensorProfile - conversion of the sensor values
+//
+// Optional params:
+//   time.Duration - polling interval
+//
+func NewGroveAnalogSensorDriver(gp *GrovePiDriver, pin string, profile *SensorProfile, i ...time.Duration) *GroveAnalogSensorDriver {
+	if profile == nil {
+		profile = NewSensorProfile(nil, nil)
+	}
+	drv := &GroveAnalogSensorDriver{
+		name:      gobot.DefaultName("GroveAnalogSensor"),
+		halt:      make(chan bool),
+		pin:       pin,
+		interval:  10 * time.Millisecond,
+		grovepi:   gp,
+		profile:   profile,
+		mutex:     &sync.Mutex{},
+		raw:       -1,
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(aio.Data)
+	drv.AddEvent(Value)
+	drv.AddEvent(LevelChange)
+	drv.AddEvent(aio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		m, level := drv.Measurement()
+		return map[string]interface{}{"raw": m.Raw, "value": m.Value, "unit": m.Unit, "level": level}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveAnalogSensorDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveAnalogSensorDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveAnalogSensorDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Pin returns the pin of the sensor
+func (d *GroveAnalogSensorDriver) Pin() string { return d.pin }
+
+// Profile returns the sensor profile
+func (d *GroveAnalogSensorDriver) Profile() *SensorProfile { return d.profile }
+
+// SetFilters sets the filters applied to the raw values before they are converted and published
+func (d *GroveAnalogSensorDriver) SetFilters(filters FilterChain) { d.filters = filters }
+
+// Start polls the sensor
+func (d *GroveAnalogSensorDriver) Start() (err error) {
+	d.filters.Reset()
+
+	go func() {
+		for {
+			raw, err := d.grovepi.AnalogRead(d.pin)
+			if err != nil {
+				d.Publish(aio.Error, err)
+			} else if filtered, ok := d.filters.Apply(float64(raw)); ok {
+				d.update(int(math.Round(filtered)))
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling the sensor
+func (d *GroveAnalogSensorDriver) Halt() (err error) {
+	d.halt <- true
+	return
+}
+
+// Measurement returns the last valid value and its level
+func (d *GroveAnalogSensorDriver) Measurement() (Measurement, string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return Measurement{Value: d.value, Unit: d.profile.Calibration.Unit, Raw: float64(d.raw)}, d.level
+}
+
+// update converts the changed raw value, values out of the valid range are reported as aio.Error
+func (d *GroveAnalogSensorDriver) update(raw int) {
+	d.mutex.Lock()
+	if raw == d.raw {
+		d.mutex.Unlock()
+		return
+	}
+	value := d.profile.Calibration.Convert(float64(raw))
+	if !d.profile.Valid(value) {
+		d.mutex.Unlock()
+		d.Publish(aio.Error, fmt.Errorf("%w: %s value %v from raw %d", ErrorOutOfRange, d.name, value, raw))
+		return
+	}
+	level := d.profile.Level(value)
+	levelChanged := level != d.level
+	d.raw, d.value, d.level = raw, value, level
+	d.mutex.Unlock()
+
+	d.Publish(aio.Data, raw)
+	d.Publish(Value, Measurement{Value: value, Unit: d.profile.Calibration.Unit, Raw: float64(raw)})
+	if levelChanged {
+		d.Publish(LevelChange, level)
+	}
+}
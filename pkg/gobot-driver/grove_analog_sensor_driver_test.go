@@ -0,0 +1,35 @@
+package gobot_driver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroveAnalogSensorProfile(t *testing.T) {
+	profile, _ := BuiltinSensorProfile("moisture")
+	d := NewGroveAnalogSensorDriver(nil, "A0", profile)
+
+	d.update(350)
+	if m, level := d.Measurement(); m.Value != 350 || level != "humid" {
+		t.Errorf("unexpected measurement %+v at level %q", m, level)
+	}
+
+	d.update(1000)
+	if m, _ := d.Measurement(); m.Raw != 350 {
+		t.Errorf("value out of the valid range should be dropped, got %+v", m)
+	}
+}
+
+func TestThermistorCalibration(t *testing.T) {
+	c, err := NewThermistorCalibration(DefaultThermistorBeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// equal resistances of the divider is the nominal 25°C
+	if v := c.Convert(511.5); math.Abs(v-25) > 1e-9 {
+		t.Errorf("expected 25°C, got %v", v)
+	}
+	if v := c.Convert(0); !math.IsNaN(v) {
+		t.Errorf("disconnected thermistor should give NaN, got %v", v)
+	}
+}
@@ -1,14 +1,26 @@
 package gobot_driver
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/drivers/gpio"
 )
 
+// noteGapDivisor sets the silence at the end of every note to 1/8 of its duration
+const noteGapDivisor = 8
+
 // GroveBuzzerDriver represents a buzzer
 // with a Grove connector
 type GroveBuzzerDriver struct {
 	*gpio.BuzzerDriver
+	mutex    *sync.Mutex
+	melodies map[string]*Melody
+	stop     chan struct{}
+	done     chan struct{}
 	gobot.Commander
 }
 
@@ -16,6 +28,8 @@ type GroveBuzzerDriver struct {
 func NewGroveBuzzerDriver(a gpio.DigitalWriter, pin string) *GroveBuzzerDriver {
 	l := &GroveBuzzerDriver{
 		BuzzerDriver: gpio.NewBuzzerDriver(a, pin),
+		mutex:        &sync.Mutex{},
+		melodies:     map[string]*Melody{},
 		Commander:    gobot.NewCommander(),
 	}
 
@@ -29,8 +43,17 @@ func NewGroveBuzzerDriver(a gpio.DigitalWriter, pin string) *GroveBuzzerDriver {
 	})
 
 	l.AddCommand("Tone", func(params map[string]interface{}) interface{} {
-		hz := params["tone"].(float64)
-		duration := params["duration"].(float64)
+		hz, err := floatParam(params, "tone")
+		if err != nil {
+			return err
+		}
+		duration, err := floatParam(params, "duration")
+		if err != nil {
+			return err
+		}
+		if hz <= 0 || duration <= 0 {
+			return fmt.Errorf("%w: tone %v Hz for %v beats", ErrorInvalidParam, hz, duration)
+		}
 		return l.Tone(hz, duration)
 	})
 
@@ -46,5 +69,173 @@ func NewGroveBuzzerDriver(a gpio.DigitalWriter, pin string) *GroveBuzzerDriver {
 		return l.Off()
 	})
 
+	l.AddCommand("Play", func(params map[string]interface{}) interface{} {
+		if _, found := params["rtttl"]; found {
+			s, err := stringParam(params, "rtttl")
+			if err != nil {
+				return err
+			}
+			m, err := ParseMelody(s)
+			if err != nil {
+				return err
+			}
+			return l.PlayMelody(m)
+		}
+		name, err := stringParam(params, "melody")
+		if err != nil {
+			return err
+		}
+		return l.Play(name)
+	})
+
+	l.AddCommand("Stop", func(params map[string]interface{}) interface{} {
+		return l.Stop()
+	})
+
+	l.AddCommand("Melodies", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"melodies": l.Melodies(), "playing": l.Playing()}
+	})
+
 	return l
 }
+
+// Halt stops the playing melody
+func (l *GroveBuzzerDriver) Halt() (err error) {
+	return l.Stop()
+}
+
+// SetMelodies sets the named melodies played by Play
+func (l *GroveBuzzerDriver) SetMelodies(melodies map[string]*Melody) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.melodies = melodies
+}
+
+// Melodies returns sorted names of the melodies
+func (l *GroveBuzzerDriver) Melodies() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	names := make([]string, 0, len(l.melodies))
+	for name := range l.melodies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Play starts playing the named melody, see PlayMelody
+func (l *GroveBuzzerDriver) Play(name string) error {
+	l.mutex.Lock()
+	m, found := l.melodies[name]
+	l.mutex.Unlock()
+
+	if !found {
+		return fmt.Errorf("%w: unknown melody %q", ErrorInvalidParam, name)
+	}
+	return l.PlayMelody(m)
+}
+
+// PlayMelody stops the playing melody and plays the new one in the background
+func (l *GroveBuzzerDriver) PlayMelody(m *Melody) error {
+	if err := l.Stop(); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	stop, done := make(chan struct{}), make(chan struct{})
+	l.stop, l.done = stop, done
+	l.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		for _, n := range m.Notes {
+			if err := l.playNote(n, stop); err != nil {
+				_ = l.Off()
+				return
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the playing melody and turns the buzzer off
+func (l *GroveBuzzerDriver) Stop() error {
+	l.mutex.Lock()
+	stop, done := l.stop, l.done
+	l.stop, l.done = nil, nil
+	l.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	if l.State() {
+		return l.Off()
+	}
+	return nil
+}
+
+// Playing returns true while a melody is played
+func (l *GroveBuzzerDriver) Playing() bool {
+	l.mutex.Lock()
+	done := l.done
+	l.mutex.Unlock()
+
+	if done == nil {
+		return false
+	}
+	select {
+	case <-done:
+		return false
+	default:
+		return true
+	}
+}
+
+// playNote keeps the buzzer on for the note and off for the pause, stop interrupts the note.
+// The pin can't be toggled at audio frequencies over I2C, so the buzzer sounds its own tone
+// and the melody keeps only its rhythm
+func (l *GroveBuzzerDriver) playNote(n Note, stop chan struct{}) error {
+	sound := time.Duration(0)
+	if n.Frequency > 0 {
+		if err := l.On(); err != nil {
+			return err
+		}
+		// short silence at the end keeps repeated notes apart
+		sound = n.Duration - n.Duration/noteGapDivisor
+	}
+	if !wait(sound, stop) {
+		return l.Off()
+	}
+	if l.State() {
+		if err := l.Off(); err != nil {
+			return err
+		}
+	}
+	wait(n.Duration-sound, stop)
+	return nil
+}
+
+// wait sleeps for the duration, it returns false when stopped earlier
+func wait(d time.Duration, stop chan struct{}) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package gobot_driver
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDigitalWriter struct {
+	mutex  sync.Mutex
+	writes int
+	level  byte
+}
+
+func (w *fakeDigitalWriter) DigitalWrite(pin string, level byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.writes++
+	w.level = level
+	return nil
+}
+
+func TestGroveBuzzerToneParams(t *testing.T) {
+	d := NewGroveBuzzerDriver(&fakeDigitalWriter{}, "D8")
+	for _, params := range []map[string]interface{}{
+		{},
+		{"tone": "A4", "duration": 1.0},
+		{"tone": 0.0, "duration": 1.0},
+	} {
+		if err, _ := d.Command("Tone")(params).(error); !errors.Is(err, ErrorInvalidParam) {
+			t.Errorf("%v: expected invalid param, got %v", params, err)
+		}
+	}
+}
+
+func TestGroveBuzzerPlayAndStop(t *testing.T) {
+	w := &fakeDigitalWriter{}
+	d := NewGroveBuzzerDriver(w, "D8")
+	m, err := ParseMelody("long:d=1,o=5,b=30:a,a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetMelodies(map[string]*Melody{"long": m})
+
+	if err := d.Play("missing"); !errors.Is(err, ErrorInvalidParam) {
+		t.Errorf("expected unknown melody error, got %v", err)
+	}
+	if err := d.Play("long"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !d.Playing() {
+		t.Fatal("melody should be playing in the background")
+	}
+
+	started := time.Now()
+	if err := d.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(started) > 100*time.Millisecond || d.Playing() {
+		t.Error("melody should stop right away")
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.writes == 0 || w.level != 0 || d.State() {
+		t.Errorf("buzzer should be toggled and left off, %d writes, level %d", w.writes, w.level)
+	}
+}
+
+func TestGroveBuzzerPlaysRhythm(t *testing.T) {
+	w := &fakeDigitalWriter{}
+	d := NewGroveBuzzerDriver(w, "D8")
+	m, err := ParseMelody("short:d=16,o=5,b=900:a,p,a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.PlayMelody(m); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for d.Playing() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	// every note is a single on and off write, the bus isn't held for the whole note
+	if w.writes != 4 || w.level != 0 {
+		t.Errorf("expected 4 writes leaving the buzzer off, got %d writes, level %d", w.writes, w.level)
+	}
+}
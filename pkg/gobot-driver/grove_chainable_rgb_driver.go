@@ -0,0 +1,351 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Chainable RGB LED patterns relative to the addressed LED
+const (
+	ChainableRGBPatternThis     = 0
+	ChainableRGBPatternAllBut   = 1
+	ChainableRGBPatternInwards  = 2
+	ChainableRGBPatternOutwards = 3
+)
+
+const animationStep = 50 * time.Millisecond
+
+// RGB is a color of a chainable LED
+type RGB struct {
+	R, G, B byte
+}
+
+// GroveChainableRGBDriver represents a chain of Grove Chainable RGB LEDs driven by the GrovePi firmware
+type GroveChainableRGBDriver struct {
+	name      string
+	pin       string
+	count     int
+	grovepi   *GrovePiDriver
+	mutex     *sync.Mutex
+	colors    []RGB
+	animation chan bool
+	done      chan struct{}
+	gobot.Commander
+}
+
+// NewGroveChainableRGBDriver creates new instance of GroveChainableRGBDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the chain is attached to
+//   pin string - digital pin of the chain
+//   count int - number of LEDs in the chain
+//
+func NewGroveChainableRGBDriver(gp *GrovePiDriver, pin string, count int) *GroveChainableRGBDriver {
+	if count < 1 {
+		count = 1
+	}
+	drv := &GroveChainableRGBDriver{
+		name:      gobot.DefaultName("GroveChainableRGB"),
+		pin:       pin,
+		count:     count,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		colors:    make([]RGB, count),
+		Commander: gobot.NewCommander(),
+	}
+
+	drv.AddCommand("SetColor", func(params map[string]interface{}) interface{} {
+		led, err := intParam(params, "led", 0, drv.count-1)
+		if err != nil {
+			return err
+		}
+		c, err := colorParams(params)
+		if err != nil {
+			return err
+		}
+		return drv.SetColor(led, c)
+	})
+
+	drv.AddCommand("Fill", func(params map[string]interface{}) interface{} {
+		c, err := colorParams(params)
+		if err != nil {
+			return err
+		}
+		return drv.Fill(c)
+	})
+
+	drv.AddCommand("Level", func(params map[string]interface{}) interface{} {
+		level, err := intParam(params, "level", 0, drv.count)
+		if err != nil {
+			return err
+		}
+		reversed, err := boolParamOr(params, "reversed", false)
+		if err != nil {
+			return err
+		}
+		c, err := colorParams(params)
+		if err != nil {
+			return err
+		}
+		return drv.Level(level, reversed, c)
+	})
+
+	drv.AddCommand("Off", func(params map[string]interface{}) interface{} {
+		return drv.Off()
+	})
+
+	drv.AddCommand("Fade", func(params map[string]interface{}) interface{} {
+		c, err := colorParams(params)
+		if err != nil {
+			return err
+		}
+		duration, err := intParamOr(params, "duration", 1000, 0, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		return drv.Fade(c, time.Duration(duration)*time.Millisecond)
+	})
+
+	drv.AddCommand("Rainbow", func(params map[string]interface{}) interface{} {
+		period, err := intParamOr(params, "period", 5000, 0, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		return drv.Rainbow(time.Duration(period) * time.Millisecond)
+	})
+
+	drv.AddCommand("Stop", func(params map[string]interface{}) interface{} {
+		drv.Stop()
+		return nil
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveChainableRGBDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveChainableRGBDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveChainableRGBDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Start initializes the chain
+func (d *GroveChainableRGBDriver) Start() (err error) {
+	return d.grovepi.ChainableRGBInit(d.pin, byte(d.count))
+}
+
+// Halt stops the animation and turns all LEDs off
+func (d *GroveChainableRGBDriver) Halt() (err error) {
+	return d.Off()
+}
+
+// Count returns the number of LEDs in the chain
+func (d *GroveChainableRGBDriver) Count() int { return d.count }
+
+// Colors returns current colors of the LEDs
+func (d *GroveChainableRGBDriver) Colors() []RGB {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]RGB{}, d.colors...)
+}
+
+// SetColor sets the color of the LED, 0 is the first LED
+func (d *GroveChainableRGBDriver) SetColor(led int, c RGB) error {
+	d.Stop()
+	return d.setColor(led, c)
+}
+
+// Fill sets the color of all LEDs
+func (d *GroveChainableRGBDriver) Fill(c RGB) error {
+	d.Stop()
+	return d.fill(c)
+}
+
+// Level sets the color of the LEDs up to the level and turns the rest off,
+// reversed level counts from the end of the chain
+func (d *GroveChainableRGBDriver) Level(level int, reversed bool, c RGB) error {
+	if level < 0 || level > d.count {
+		return fmt.Errorf("%w: level %d of %d LEDs", ErrorOutOfRange, level, d.count)
+	}
+	d.Stop()
+
+	if err := d.grovepi.ChainableRGBLevel(d.pin, byte(level), reversed, c.R, c.G, c.B); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for i := range d.colors {
+		lit := i < level
+		if reversed {
+			lit = i >= d.count-level
+		}
+		if lit {
+			d.colors[i] = c
+		} else {
+			d.colors[i] = RGB{}
+		}
+	}
+	return nil
+}
+
+// Off stops the animation and turns all LEDs off
+func (d *GroveChainableRGBDriver) Off() error {
+	return d.Fill(RGB{})
+}
+
+// Fade changes the color of all LEDs from the color of the first LED to the given one over the duration
+func (d *GroveChainableRGBDriver) Fade(to RGB, duration time.Duration) error {
+	from := d.Colors()[0]
+	steps := int(duration / animationStep)
+	if steps < 1 {
+		return d.Fill(to)
+	}
+
+	return d.animate(func(step int) (bool, error) {
+		k := float64(step+1) / float64(steps)
+		return step+1 >= steps, d.fill(RGB{
+			R: blend(from.R, to.R, k),
+			G: blend(from.G, to.G, k),
+			B: blend(from.B, to.B, k),
+		})
+	})
+}
+
+// Rainbow cycles the LEDs through the rainbow colors, one cycle takes the period, until stopped
+func (d *GroveChainableRGBDriver) Rainbow(period time.Duration) error {
+	steps := int(period / animationStep)
+	if steps < 1 {
+		steps = 1
+	}
+
+	return d.animate(func(step int) (bool, error) {
+		offset := float64(step%steps) / float64(steps) * 360
+		for led := 0; led < d.count; led++ {
+			hue := math.Mod(offset+float64(led)*360/float64(d.count), 360)
+			if err := d.setColor(led, hueToRGB(hue)); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+}
+
+// Stop stops the running animation and waits until its last frame is shown
+func (d *GroveChainableRGBDriver) Stop() {
+	d.mutex.Lock()
+	halt, done := d.animation, d.done
+	d.animation, d.done = nil, nil
+	d.mutex.Unlock()
+
+	if halt != nil {
+		close(halt)
+		<-done
+	}
+}
+
+// animate runs the frame function every animation step until it reports the end or the animation is stopped
+func (d *GroveChainableRGBDriver) animate(frame func(step int) (bool, error)) error {
+	d.Stop()
+
+	d.mutex.Lock()
+	halt, done := make(chan bool), make(chan struct{})
+	d.animation, d.done = halt, done
+	d.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		for step := 0; ; step++ {
+			if done, err := frame(step); done || err != nil {
+				return
+			}
+
+			select {
+			case <-time.After(animationStep):
+			case <-halt:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (d *GroveChainableRGBDriver) setColor(led int, c RGB) error {
+	if led < 0 || led >= d.count {
+		return fmt.Errorf("%w: LED %d of %d", ErrorOutOfRange, led, d.count)
+	}
+	if err := d.grovepi.ChainableRGBPattern(d.pin, ChainableRGBPatternThis, byte(led), c.R, c.G, c.B); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.colors[led] = c
+	return nil
+}
+
+func (d *GroveChainableRGBDriver) fill(c RGB) error {
+	if err := d.grovepi.ChainableRGBModulo(d.pin, 0, 1, c.R, c.G, c.B); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for i := range d.colors {
+		d.colors[i] = c
+	}
+	return nil
+}
+
+func colorParams(params map[string]interface{}) (RGB, error) {
+	var c [3]byte
+	for i, name := range []string{"r", "g", "b"} {
+		v, err := floatParamOr(params, name, 0)
+		if err != nil {
+			return RGB{}, err
+		}
+		if v < 0 || v > 255 {
+			return RGB{}, fmt.Errorf("%w: %s is %v", ErrorInvalidParam, name, v)
+		}
+		c[i] = byte(v)
+	}
+	return RGB{R: c[0], G: c[1], B: c[2]}, nil
+}
+
+func blend(from, to byte, k float64) byte {
+	return byte(math.Round(float64(from) + (float64(to)-float64(from))*k))
+}
+
+// hueToRGB returns fully saturated color of the hue, 0 to 360 degrees
+func hueToRGB(hue float64) RGB {
+	x := 1 - math.Abs(math.Mod(hue/60, 2)-1)
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = 1, x, 0
+	case hue < 120:
+		r, g, b = x, 1, 0
+	case hue < 180:
+		r, g, b = 0, 1, x
+	case hue < 240:
+		r, g, b = 0, x, 1
+	case hue < 300:
+		r, g, b = x, 0, 1
+	default:
+		r, g, b = 1, 0, x
+	}
+	return RGB{R: byte(math.Round(r * 255)), G: byte(math.Round(g * 255)), B: byte(math.Round(b * 255))}
+}
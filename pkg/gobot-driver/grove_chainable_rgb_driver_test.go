@@ -0,0 +1,148 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func (c *lockedConnection) writes() [][]byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([][]byte{}, c.written...)
+}
+
+func newTestChainableRGB(t *testing.T, count int) (*GroveChainableRGBDriver, *lockedConnection) {
+	connection := &lockedConnection{fakeConnection: &fakeConnection{}}
+	gp := NewGrovePiDriver(&fakeConnector{connection: connection.fakeConnection})
+	if err := gp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	gp.connection = connection
+	return NewGroveChainableRGBDriver(gp, "D7", count), connection
+}
+
+func TestGrovePiDriverChainableRGBCommands(t *testing.T) {
+	d, connector := newTestGrovePiDriver()
+
+	if err := d.ChainableRGBInit("D7", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ChainableRGBTest("D7", 3, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ChainableRGBPattern("D7", ChainableRGBPatternAllBut, 1, 10, 20, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ChainableRGBModulo("D7", 1, 2, 40, 50, 60); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ChainableRGBLevel("D7", 2, true, 70, 80, 90); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]byte{
+		{CommandChainableRGBInit, 7, 3, 0},
+		{CommandChainableRGBTest, 7, 3, 4},
+		{CommandChainableRGBStoreColor, 10, 20, 30},
+		{CommandChainableRGBPattern, 7, ChainableRGBPatternAllBut, 1},
+		{CommandChainableRGBStoreColor, 40, 50, 60},
+		{CommandChainableRGBModulo, 7, 1, 2},
+		{CommandChainableRGBStoreColor, 70, 80, 90},
+		{CommandChainableRGBLevel, 7, 2, 1},
+	}
+	written := connector.connection.written
+	if len(written) != len(expected) {
+		t.Fatalf("expected %d commands, got %v", len(expected), written)
+	}
+	for i, e := range expected {
+		if string(written[i]) != string(e) {
+			t.Errorf("command %d: expected %v, got %v", i, e, written[i])
+		}
+	}
+
+	if err := d.ChainableRGBPattern("D7", 4, 0, 0, 0, 0); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected unknown pattern error, got %v", err)
+	}
+	if err := d.ChainableRGBModulo("D7", 0, 0, 0, 0, 0); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected zero divisor error, got %v", err)
+	}
+}
+
+func TestGroveChainableRGBLevel(t *testing.T) {
+	d, _ := newTestChainableRGB(t, 4)
+	red := RGB{R: 255}
+
+	if err := d.Level(3, true, red); err != nil {
+		t.Fatal(err)
+	}
+	colors := d.Colors()
+	if colors[0] != (RGB{}) || colors[1] != red || colors[3] != red {
+		t.Errorf("expected the last 3 LEDs lit, got %v", colors)
+	}
+	if err := d.Level(5, false, red); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected level out of range, got %v", err)
+	}
+	if err := d.SetColor(4, red); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected LED out of range, got %v", err)
+	}
+}
+
+func TestGroveChainableRGBFade(t *testing.T) {
+	d, _ := newTestChainableRGB(t, 2)
+	to := RGB{R: 100, G: 50}
+
+	if err := d.Fade(to, 3*animationStep); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for d.Colors()[1] != to && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if colors := d.Colors(); colors[0] != to || colors[1] != to {
+		t.Errorf("expected faded to %v, got %v", to, colors)
+	}
+}
+
+func TestGroveChainableRGBHaltStopsAnimation(t *testing.T) {
+	d, connection := newTestChainableRGB(t, 3)
+
+	if err := d.Rainbow(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * animationStep)
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	n := len(connection.writes())
+
+	time.Sleep(2 * animationStep)
+	if written := connection.writes(); len(written) != n || written[n-1][0] != CommandChainableRGBModulo {
+		t.Errorf("no frame should follow the halt, got %v after %d commands", written[n-1:], n)
+	}
+	for _, c := range d.Colors() {
+		if c != (RGB{}) {
+			t.Errorf("LEDs should be off, got %v", d.Colors())
+		}
+	}
+}
+
+func TestGroveChainableRGBCommandParams(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGroveChainableRGBDriver(gp, "D7", 3)
+
+	for command, params := range map[string]map[string]interface{}{
+		"SetColor": {"led": 3.0},
+		"Level":    {"level": 259.0},
+		"Fade":     {"duration": -1.0},
+		"Rainbow":  {"period": 1e300},
+	} {
+		err, _ := d.Command(command)(params).(error)
+		if !errors.Is(err, ErrorInvalidParam) {
+			t.Errorf("%s: expected invalid param, got %v", command, err)
+		}
+	}
+	if n := len(connector.connection.written); n != 0 {
+		t.Errorf("invalid params shouldn't be written, got %d writes", n)
+	}
+}
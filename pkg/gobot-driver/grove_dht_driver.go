@@ -1,21 +1,35 @@
 package gobot_driver
 
 import (
+	"errors"
+	"fmt"
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/drivers/aio"
+	"sync"
 	"time"
 )
 
+// ErrorNoReading is returned for the derived metrics before the sensor was read
+var ErrorNoReading = errors.New("no reading yet")
+
 // GroveTemperatureAndHumidityDriver represents a Grove Temperature and Humidity Sensor
-// Temperature is reported in Celsius degrees
+// Temperature is reported in Celsius degrees unless other unit is set
 type GroveTemperatureAndHumidityDriver struct {
 	name     string
 	halt     chan bool
 	pin      string
+	mutex    *sync.Mutex
 	temp     float32
 	humid    float32
+	valid    bool
 	interval time.Duration
+	module   byte
+	unit     string
 	grovepi  *GrovePiDriver
+	tFilters FilterChain
+	hFilters FilterChain
+	tPolicy  publisher
+	hPolicy  publisher
 	gobot.Eventer
 	gobot.Commander
 }
@@ -23,6 +37,11 @@ type GroveTemperatureAndHumidityDriver struct {
 const (
 	Temperature = "temperature"
 	Humidity    = "humidity"
+
+	// Derived metrics published when temperature or humidity changes, their data is float32
+	DewPoint         = "dewPoint"
+	HeatIndex        = "heatIndex"
+	AbsoluteHumidity = "absoluteHumidity"
 )
 
 // NewGroveTemperatureAndHumidityDriver creates new instance of GroveUltrasonicRangerDriver
@@ -38,8 +57,12 @@ func NewGroveTemperatureAndHumidityDriver(gp *GrovePiDriver, pin string, i ...ti
 		name:      gobot.DefaultName("TemperatureAndHumiditySensor"),
 		halt:      make(chan bool),
 		pin:       pin,
+		mutex:     &sync.Mutex{},
 		grovepi:   gp,
 		interval:  600 * time.Millisecond,
+		unit:      Celsius,
+		tPolicy:   publisher{policy: DefaultPublishPolicy},
+		hPolicy:   publisher{policy: DefaultPublishPolicy},
 		Eventer:   gobot.NewEventer(),
 		Commander: gobot.NewCommander(),
 	}
@@ -50,6 +73,9 @@ func NewGroveTemperatureAndHumidityDriver(gp *GrovePiDriver, pin string, i ...ti
 
 	drv.AddEvent(Temperature)
 	drv.AddEvent(Humidity)
+	drv.AddEvent(DewPoint)
+	drv.AddEvent(HeatIndex)
+	drv.AddEvent(AbsoluteHumidity)
 	drv.AddEvent(aio.Error)
 
 	drv.AddCommand("ReadTemperature", func(params map[string]interface{}) interface{} {
@@ -62,6 +88,30 @@ func NewGroveTemperatureAndHumidityDriver(gp *GrovePiDriver, pin string, i ...ti
 			"humidity": drv.Humidity()}
 	})
 
+	drv.AddCommand("ReadAll", func(params map[string]interface{}) interface{} {
+		c, err := drv.Climate()
+		if err != nil {
+			return map[string]interface{}{
+				"temperature": drv.Temperature(),
+				"humidity":    drv.Humidity(),
+				"unit":        drv.Unit(),
+				"error":       err.Error()}
+		}
+		return c
+	})
+
+	drv.AddCommand("ReadDerived", func(params map[string]interface{}) interface{} {
+		c, err := drv.Climate()
+		if err != nil {
+			return map[string]interface{}{"unit": drv.Unit(), "error": err.Error()}
+		}
+		return map[string]interface{}{
+			"dewPoint":         c.DewPoint,
+			"heatIndex":        c.HeatIndex,
+			"absoluteHumidity": c.AbsoluteHumidity,
+			"unit":             c.Unit}
+	})
+
 	return drv
 }
 
@@ -79,8 +129,15 @@ func (d *GroveTemperatureAndHumidityDriver) Connection() gobot.Connection {
 // Start initialized the GrovePi
 func (d *GroveTemperatureAndHumidityDriver) Start() (err error) {
 
+	d.mutex.Lock()
 	d.temp = 0
 	d.humid = 0
+	d.valid = false
+	d.mutex.Unlock()
+	d.tFilters.Reset()
+	d.hFilters.Reset()
+	d.tPolicy.reset()
+	d.hPolicy.reset()
 
 	go func() {
 		for {
@@ -91,14 +148,7 @@ func (d *GroveTemperatureAndHumidityDriver) Start() (err error) {
 			if err != nil {
 				d.Publish(aio.Error, err)
 			} else {
-				if newT != d.temp || d.temp == 0 {
-					d.temp = newT
-					d.Publish(Temperature, d.temp)
-				}
-				if newH != d.humid || d.humid == 0 {
-					d.humid = newH
-					d.Publish(Humidity, d.humid)
-				}
+				d.sample(newT, newH, time.Now())
 			}
 
 			select {
@@ -112,21 +162,147 @@ func (d *GroveTemperatureAndHumidityDriver) Start() (err error) {
 	return
 }
 
+// sample filters a reading and publishes the values selected by the publish policies
+func (d *GroveTemperatureAndHumidityDriver) sample(newT, newH float32, now time.Time) {
+	published := false
+	if t, ok := d.tFilters.Apply(float64(newT)); ok {
+		d.mutex.Lock()
+		d.temp = float32(t)
+		d.valid = true
+		unit := d.unit
+		d.mutex.Unlock()
+		// the policy compares the published values, so its epsilon is in the driver unit
+		converted, _ := ConvertTemperature(t, unit)
+		if d.tPolicy.publish(converted, now) {
+			d.Publish(Temperature, float32(converted))
+			published = true
+		}
+	}
+	if h, ok := d.hFilters.Apply(float64(newH)); ok {
+		d.mutex.Lock()
+		d.humid = float32(h)
+		d.mutex.Unlock()
+		if d.hPolicy.publish(h, now) {
+			d.Publish(Humidity, d.Humidity())
+			published = true
+		}
+	}
+	if published {
+		d.publishDerived()
+	}
+}
+
+// SetModule sets the DHT module type, DHT11 by default
+func (d *GroveTemperatureAndHumidityDriver) SetModule(module byte) error {
+	if _, found := dhtRanges[module]; !found {
+		return fmt.Errorf("%w: DHT module type %d", ErrorOutOfRange, module)
+	}
+	d.module = module
+	return nil
+}
+
+// SetFilters sets the filters applied to the temperature and humidity before they are published
+func (d *GroveTemperatureAndHumidityDriver) SetFilters(temperature, humidity FilterChain) {
+	d.tFilters, d.hFilters = temperature, humidity
+}
+
+// SetPublishPolicy sets which temperature and humidity samples are published, the policy applies to each of them
+func (d *GroveTemperatureAndHumidityDriver) SetPublishPolicy(p PublishPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	d.tPolicy = publisher{policy: p}
+	d.hPolicy = publisher{policy: p}
+	return nil
+}
+
 // Halt returns true if devices is halted successfully
 func (d *GroveTemperatureAndHumidityDriver) Halt() (err error) {
 	d.halt <- true
 	return
 }
 
+// SetUnit sets the unit of the published temperatures, Celsius, Fahrenheit or Kelvin
+func (d *GroveTemperatureAndHumidityDriver) SetUnit(unit string) error {
+	if _, err := ConvertTemperature(0, unit); err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.unit = unit
+	return nil
+}
+
+// Unit returns the unit of the temperatures
+func (d *GroveTemperatureAndHumidityDriver) Unit() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.unit
+}
+
+// Temperature returns the last temperature in the driver unit
 func (d *GroveTemperatureAndHumidityDriver) Temperature() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	t, _ := ConvertTemperature(float64(d.temp), d.unit)
+	return float32(t)
+}
+
+// Celsius returns the last temperature in Celsius degrees
+func (d *GroveTemperatureAndHumidityDriver) Celsius() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	return d.temp
 }
 
+// CelsiusReading returns the last temperature in Celsius degrees, ok is false before the first reading,
+// it can be used as TemperatureSource
+func (d *GroveTemperatureAndHumidityDriver) CelsiusReading() (celsius float64, ok bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return float64(d.temp), d.valid
+}
+
+// Humidity returns the last relative humidity in %
 func (d *GroveTemperatureAndHumidityDriver) Humidity() float32 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	return d.humid
 }
 
+// Climate returns the last temperature and humidity with the derived metrics,
+// it fails before the first reading and when the metrics are undefined, e.g. at 0 % humidity
+func (d *GroveTemperatureAndHumidityDriver) Climate() (Climate, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.valid {
+		return Climate{}, ErrorNoReading
+	}
+	return NewClimate(float64(d.temp), float64(d.humid), d.unit)
+}
+
+// publishDerived publishes the derived metrics once both temperature and humidity were read and the metrics are defined
+func (d *GroveTemperatureAndHumidityDriver) publishDerived() {
+	if !d.tPolicy.published || !d.hPolicy.published {
+		return
+	}
+	c, err := d.Climate()
+	if err != nil {
+		return
+	}
+	d.Publish(DewPoint, c.DewPoint)
+	d.Publish(HeatIndex, c.HeatIndex)
+	d.Publish(AbsoluteHumidity, c.AbsoluteHumidity)
+}
+
 //Read performs a read on temperature and humidity sensor.
 func (d *GroveTemperatureAndHumidityDriver) Read() (float32, float32, error) {
-	return d.grovepi.ReadDHT(d.pin)
+	return d.grovepi.ReadDHTModule(d.pin, d.module)
 }
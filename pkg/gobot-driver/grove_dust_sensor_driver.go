@@ -0,0 +1,160 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// dustSensorFirmwareWindow is the sampling window of the firmware low pulse counter
+const dustSensorFirmwareWindow = 30 * time.Second
+
+// GroveDustSensorDriver represents a Grove dust sensor attached to the interrupt pin D2.
+// Low pulse occupancy of the firmware samples is summed up over the sampling window and converted
+// to concentration in particles per 0.01 cubic foot, published as aio.Data float64
+type GroveDustSensorDriver struct {
+	name          string
+	halt          chan bool
+	interval      time.Duration
+	window        time.Duration
+	grovepi       *GrovePiDriver
+	mutex         *sync.Mutex
+	occupancy     uint64
+	sampled       time.Duration
+	ratio         float64
+	concentration float64
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveDustSensorDriver creates new instance of GroveDustSensorDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the sensor is attached to
+//
+// Optional params:
+//   time.Duration - polling interval of the firmware counter
+//
+func NewGroveDustSensorDriver(gp *GrovePiDriver, i ...time.Duration) *GroveDustSensorDriver {
+	drv := &GroveDustSensorDriver{
+		name:      gobot.DefaultName("GroveDustSensor"),
+		halt:      make(chan bool),
+		interval:  time.Second,
+		window:    dustSensorFirmwareWindow,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(aio.Data)
+	drv.AddEvent(aio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"concentration": drv.Concentration(), "ratio": drv.Ratio()}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveDustSensorDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveDustSensorDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveDustSensorDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// SetWindow sets the sampling window, it is rounded up to the firmware window of 30 seconds
+func (d *GroveDustSensorDriver) SetWindow(window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("%w: window %v", ErrorOutOfRange, window)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.window = window
+	return nil
+}
+
+// Start enables the sensor and polls its counter
+func (d *GroveDustSensorDriver) Start() (err error) {
+	if err = d.grovepi.DustSensorEnable(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			occupancy, ok, err := d.grovepi.DustSensorRead()
+			if err != nil {
+				d.Publish(aio.Error, err)
+			} else if ok {
+				d.sample(occupancy)
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling and disables the sensor
+func (d *GroveDustSensorDriver) Halt() (err error) {
+	d.halt <- true
+	return d.grovepi.DustSensorDisable()
+}
+
+// Concentration returns the last concentration in particles per 0.01 cubic foot
+func (d *GroveDustSensorDriver) Concentration() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.concentration
+}
+
+// Ratio returns the last low pulse occupancy ratio in percent
+func (d *GroveDustSensorDriver) Ratio() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.ratio
+}
+
+// sample adds the firmware sample and publishes the concentration when the window is complete
+func (d *GroveDustSensorDriver) sample(occupancy uint32) {
+	d.mutex.Lock()
+	d.occupancy += uint64(occupancy)
+	d.sampled += dustSensorFirmwareWindow
+	if d.sampled < d.window {
+		d.mutex.Unlock()
+		return
+	}
+	d.ratio = float64(d.occupancy) / float64(d.sampled/time.Microsecond) * 100
+	d.concentration = dustConcentration(d.ratio)
+	d.occupancy, d.sampled = 0, 0
+	concentration := d.concentration
+	d.mutex.Unlock()
+
+	d.Publish(aio.Data, concentration)
+}
+
+// dustConcentration converts the occupancy ratio to particles per 0.01 cubic foot using the sensor characteristic
+func dustConcentration(ratio float64) float64 {
+	return 1.1*math.Pow(ratio, 3) - 3.8*math.Pow(ratio, 2) + 520*ratio + 0.62
+}
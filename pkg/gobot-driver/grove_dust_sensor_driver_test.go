@@ -0,0 +1,19 @@
+package gobot_driver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroveDustSensorConcentration(t *testing.T) {
+	d := NewGroveDustSensorDriver(nil)
+
+	// 300 ms of low pulses in 30 s is 1 percent
+	d.sample(300000)
+	if ratio := d.Ratio(); math.Abs(ratio-1) > 1e-9 {
+		t.Errorf("expected ratio 1, got %v", ratio)
+	}
+	if c := d.Concentration(); math.Abs(c-(1.1-3.8+520+0.62)) > 1e-9 {
+		t.Errorf("unexpected concentration %v", c)
+	}
+}
@@ -0,0 +1,246 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// Encoder events, their data is int
+const (
+	EncoderDelta    = "delta"
+	EncoderPosition = "position"
+)
+
+// encoderSteps is the number of steps after which the firmware counter wraps
+const encoderSteps = 24
+
+// GroveEncoderDriver represents a Grove encoder counted by the GrovePi firmware
+// Position is the sum of steps since start or reset, optionally limited to a range
+type GroveEncoderDriver struct {
+	name     string
+	halt     chan bool
+	pin      string
+	interval time.Duration
+	grovepi  *GrovePiDriver
+	mutex    *sync.Mutex
+	counter  int
+	started  bool
+	position int
+	limited  bool
+	min, max int
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveEncoderDriver creates new instance of GroveEncoderDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the encoder is attached to
+//   pin string - digital pin of the encoder
+//
+// Optional params:
+//   time.Duration - polling interval of the firmware counter
+//
+func NewGroveEncoderDriver(gp *GrovePiDriver, pin string, i ...time.Duration) *GroveEncoderDriver {
+	drv := &GroveEncoderDriver{
+		name:      gobot.DefaultName("GroveEncoder"),
+		halt:      make(chan bool),
+		pin:       pin,
+		interval:  50 * time.Millisecond,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(EncoderDelta)
+	drv.AddEvent(EncoderPosition)
+	drv.AddEvent(aio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"position": drv.Position()}
+	})
+
+	drv.AddCommand("Reset", func(params map[string]interface{}) interface{} {
+		position, err := intParamOr(params, "position", 0, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		return drv.Reset(position)
+	})
+
+	drv.AddCommand("SetRange", func(params map[string]interface{}) interface{} {
+		min, err := intParam(params, "min", math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		max, err := intParam(params, "max", math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		return drv.SetRange(min, max)
+	})
+
+	drv.AddCommand("ClearRange", func(params map[string]interface{}) interface{} {
+		drv.ClearRange()
+		return nil
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveEncoderDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveEncoderDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveEncoderDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Start enables the encoder and polls its counter
+func (d *GroveEncoderDriver) Start() (err error) {
+	if err = d.grovepi.EncoderEnable(d.pin); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	d.started = false
+	d.mutex.Unlock()
+
+	go func() {
+		for {
+			counter, ok, err := d.grovepi.EncoderRead()
+			if err != nil {
+				d.Publish(aio.Error, err)
+			} else if ok {
+				d.update(int(counter))
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling and disables the encoder
+func (d *GroveEncoderDriver) Halt() (err error) {
+	d.halt <- true
+	return d.grovepi.EncoderDisable(d.pin)
+}
+
+// Position returns the absolute position
+func (d *GroveEncoderDriver) Position() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.position
+}
+
+// Reset sets the absolute position
+func (d *GroveEncoderDriver) Reset(position int) error {
+	d.mutex.Lock()
+	if d.limited && (position < d.min || position > d.max) {
+		d.mutex.Unlock()
+		return fmt.Errorf("%w: position %d out of %d..%d", ErrorOutOfRange, position, d.min, d.max)
+	}
+	d.position = position
+	d.mutex.Unlock()
+
+	d.Publish(EncoderPosition, position)
+	return nil
+}
+
+// SetRange limits the absolute position to the range, current position is clamped
+func (d *GroveEncoderDriver) SetRange(min, max int) error {
+	if max < min {
+		return fmt.Errorf("%w: range %d..%d", ErrorOutOfRange, min, max)
+	}
+
+	d.mutex.Lock()
+	d.limited, d.min, d.max = true, min, max
+	position := d.clamp(d.position)
+	changed := position != d.position
+	d.position = position
+	d.mutex.Unlock()
+
+	if changed {
+		d.Publish(EncoderPosition, position)
+	}
+	return nil
+}
+
+// ClearRange removes the position limits
+func (d *GroveEncoderDriver) ClearRange() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.limited = false
+}
+
+// update applies the firmware counter, the first read after start only sets the reference
+func (d *GroveEncoderDriver) update(counter int) {
+	d.mutex.Lock()
+	if !d.started {
+		d.started = true
+		d.counter = counter
+		d.mutex.Unlock()
+		return
+	}
+
+	delta := stepsDelta(d.counter, counter)
+	d.counter = counter
+	if delta == 0 {
+		d.mutex.Unlock()
+		return
+	}
+	position := d.clamp(d.position + delta)
+	changed := position != d.position
+	d.position = position
+	d.mutex.Unlock()
+
+	d.Publish(EncoderDelta, delta)
+	if changed {
+		d.Publish(EncoderPosition, position)
+	}
+}
+
+// clamp limits the position to the range, the caller must hold the mutex
+func (d *GroveEncoderDriver) clamp(position int) int {
+	if !d.limited {
+		return position
+	}
+	if position < d.min {
+		return d.min
+	}
+	if position > d.max {
+		return d.max
+	}
+	return position
+}
+
+// stepsDelta returns the shortest signed distance between wrapping counter values
+func stepsDelta(from, to int) int {
+	delta := (to - from) % encoderSteps
+	if delta > encoderSteps/2 {
+		delta -= encoderSteps
+	} else if delta <= -encoderSteps/2 {
+		delta += encoderSteps
+	}
+	return delta
+}
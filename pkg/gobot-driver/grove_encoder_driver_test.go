@@ -0,0 +1,66 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStepsDelta(t *testing.T) {
+	cases := []struct{ from, to, delta int }{
+		{0, 1, 1},
+		{5, 3, -2},
+		{23, 0, 1},
+		{0, 23, -1},
+		{0, 12, 12},
+		{12, 0, 12},
+	}
+	for _, c := range cases {
+		if delta := stepsDelta(c.from, c.to); delta != c.delta {
+			t.Errorf("stepsDelta(%d, %d) = %d, want %d", c.from, c.to, delta, c.delta)
+		}
+	}
+}
+
+func TestGroveEncoderRange(t *testing.T) {
+	d := NewGroveEncoderDriver(nil, "2")
+	if err := d.SetRange(0, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	d.update(20)
+	d.update(22)
+	d.update(2)
+	if position := d.Position(); position != 6 {
+		t.Errorf("position = %d, want 6", position)
+	}
+
+	d.update(14)
+	if position := d.Position(); position != 10 {
+		t.Errorf("position = %d, want clamped 10", position)
+	}
+
+	if err := d.Reset(11); err == nil {
+		t.Error("reset out of range should fail")
+	}
+	if err := d.SetRange(5, 1); err == nil {
+		t.Error("reversed range should fail")
+	}
+}
+
+func TestGroveEncoderCommandParams(t *testing.T) {
+	gp, _ := newTestGrovePiDriver()
+	d := NewGroveEncoderDriver(gp, "D2")
+
+	for command, params := range map[string]map[string]interface{}{
+		"Reset":    {"position": 1e20},
+		"SetRange": {"min": 0.0, "max": 1e20},
+	} {
+		err, _ := d.Command(command)(params).(error)
+		if !errors.Is(err, ErrorInvalidParam) {
+			t.Errorf("%s: expected invalid param, got %v", command, err)
+		}
+	}
+	if d.Position() != 0 {
+		t.Errorf("invalid position shouldn't be set, got %d", d.Position())
+	}
+}
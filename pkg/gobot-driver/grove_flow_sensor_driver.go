@@ -0,0 +1,187 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+const (
+	// DefaultFlowKFactor is the pulse frequency in Hz per L/min of the Grove water flow sensor
+	DefaultFlowKFactor = 7.5
+
+	// flowFirmwareWindow is the sampling window of the firmware pulse counter
+	flowFirmwareWindow = 2 * time.Second
+)
+
+// GroveFlowSensorDriver represents a Grove water flow sensor attached to the interrupt pin D2.
+// Pulses of the firmware samples are summed up over the sampling window and converted
+// to flow rate in L/min, published as aio.Data float64
+type GroveFlowSensorDriver struct {
+	name     string
+	halt     chan bool
+	interval time.Duration
+	window   time.Duration
+	kFactor  float64
+	grovepi  *GrovePiDriver
+	mutex    *sync.Mutex
+	pulses   uint64
+	sampled  time.Duration
+	rate     float64
+	volume   float64
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveFlowSensorDriver creates new instance of GroveFlowSensorDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the sensor is attached to
+//
+// Optional params:
+//   time.Duration - polling interval of the firmware counter
+//
+func NewGroveFlowSensorDriver(gp *GrovePiDriver, i ...time.Duration) *GroveFlowSensorDriver {
+	drv := &GroveFlowSensorDriver{
+		name:      gobot.DefaultName("GroveFlowSensor"),
+		halt:      make(chan bool),
+		interval:  500 * time.Millisecond,
+		window:    flowFirmwareWindow,
+		kFactor:   DefaultFlowKFactor,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(aio.Data)
+	drv.AddEvent(aio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"rate": drv.Rate(), "volume": drv.Volume()}
+	})
+
+	drv.AddCommand("ResetVolume", func(params map[string]interface{}) interface{} {
+		drv.ResetVolume()
+		return nil
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveFlowSensorDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveFlowSensorDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveFlowSensorDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// SetWindow sets the sampling window, it is rounded up to the firmware window of 2 seconds
+func (d *GroveFlowSensorDriver) SetWindow(window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("%w: window %v", ErrorOutOfRange, window)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.window = window
+	return nil
+}
+
+// SetKFactor sets the pulse frequency in Hz per L/min of the sensor
+func (d *GroveFlowSensorDriver) SetKFactor(k float64) error {
+	if k <= 0 {
+		return fmt.Errorf("%w: K-factor %v", ErrorOutOfRange, k)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.kFactor = k
+	return nil
+}
+
+// Start enables the sensor and polls its counter
+func (d *GroveFlowSensorDriver) Start() (err error) {
+	if err = d.grovepi.FlowEnable(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			pulses, ok, err := d.grovepi.FlowRead()
+			if err != nil {
+				d.Publish(aio.Error, err)
+			} else if ok {
+				d.sample(pulses)
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling and disables the sensor
+func (d *GroveFlowSensorDriver) Halt() (err error) {
+	d.halt <- true
+	return d.grovepi.FlowDisable()
+}
+
+// Rate returns the last flow rate in L/min
+func (d *GroveFlowSensorDriver) Rate() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.rate
+}
+
+// Volume returns the volume in liters since start or reset
+func (d *GroveFlowSensorDriver) Volume() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.volume
+}
+
+// ResetVolume resets the volume
+func (d *GroveFlowSensorDriver) ResetVolume() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.volume = 0
+}
+
+// sample adds the firmware sample and publishes the flow rate when the window is complete
+func (d *GroveFlowSensorDriver) sample(pulses uint16) {
+	d.mutex.Lock()
+	d.pulses += uint64(pulses)
+	d.sampled += flowFirmwareWindow
+	d.volume += float64(pulses) / d.kFactor / 60
+	if d.sampled < d.window {
+		d.mutex.Unlock()
+		return
+	}
+	d.rate = float64(d.pulses) / d.sampled.Seconds() / d.kFactor
+	d.pulses, d.sampled = 0, 0
+	rate := d.rate
+	d.mutex.Unlock()
+
+	d.Publish(aio.Data, rate)
+}
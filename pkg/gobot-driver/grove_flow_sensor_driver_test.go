@@ -0,0 +1,43 @@
+package gobot_driver
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGrovePiDriverFlowRead(t *testing.T) {
+	d, _ := newTestGrovePiDriver([]byte{CommandFlowRead, counterReady, 0x2C, 0x01})
+
+	pulses, ok, err := d.FlowRead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || pulses != 300 {
+		t.Errorf("expected 300 pulses, got %d, ok %v", pulses, ok)
+	}
+}
+
+func TestGroveFlowSensorWindow(t *testing.T) {
+	d := NewGroveFlowSensorDriver(nil)
+	if err := d.SetWindow(4 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	d.sample(30)
+	if rate := d.Rate(); rate != 0 {
+		t.Errorf("rate published before the window is complete: %v", rate)
+	}
+	d.sample(30)
+	// 60 pulses in 4 seconds is 15 Hz, 2 L/min with the default K-factor
+	if rate := d.Rate(); math.Abs(rate-2) > 1e-9 {
+		t.Errorf("expected 2 L/min, got %v", rate)
+	}
+	if volume := d.Volume(); math.Abs(volume-60/DefaultFlowKFactor/60) > 1e-9 {
+		t.Errorf("unexpected volume %v", volume)
+	}
+
+	if err := d.SetKFactor(0); err == nil {
+		t.Error("zero K-factor should fail")
+	}
+}
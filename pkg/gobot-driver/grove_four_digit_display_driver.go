@@ -0,0 +1,386 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// Grove4DigitDisplayDriver represents a Grove 4-Digit Display driven by the GrovePi firmware
+type Grove4DigitDisplayDriver struct {
+	name       string
+	pin        string
+	grovepi    *GrovePiDriver
+	mutex      *sync.Mutex
+	segments   [4]byte
+	colon      bool
+	brightness byte
+	clockMode  bool
+	animation  chan bool
+	done       chan struct{}
+	gobot.Commander
+}
+
+const (
+	// DefaultFourDigitBrightness is the brightness set on start
+	DefaultFourDigitBrightness = 2
+	// FourDigitMaxBrightness is the brightest level of the display
+	FourDigitMaxBrightness = 7
+
+	fourDigitColon    = 0x80
+	fourDigitMinus    = 0x40
+	fourDigitPosition = 4
+	clockInterval     = 500 * time.Millisecond
+
+	// DefaultScrollInterval is the time every scrolled position is shown
+	DefaultScrollInterval = 300 * time.Millisecond
+)
+
+// fourDigitSegments are segments of hexadecimal digits
+var fourDigitSegments = [16]byte{
+	0x3f, 0x06, 0x5b, 0x4f, 0x66, 0x6d, 0x7d, 0x07,
+	0x7f, 0x6f, 0x77, 0x7c, 0x39, 0x5e, 0x79, 0x71,
+}
+
+// NewGrove4DigitDisplayDriver creates new instance of Grove4DigitDisplayDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the display is attached to
+//   pin string - digital pin of the display
+//
+func NewGrove4DigitDisplayDriver(gp *GrovePiDriver, pin string) *Grove4DigitDisplayDriver {
+	drv := &Grove4DigitDisplayDriver{
+		name:       gobot.DefaultName("Grove4DigitDisplay"),
+		pin:        pin,
+		grovepi:    gp,
+		mutex:      &sync.Mutex{},
+		brightness: DefaultFourDigitBrightness,
+		Commander:  gobot.NewCommander(),
+	}
+
+	drv.AddCommand("ShowNumber", func(params map[string]interface{}) interface{} {
+		number, err := intParam(params, "number", -999, 9999)
+		if err != nil {
+			return err
+		}
+		leadingZeros, err := boolParamOr(params, "leadingZeros", false)
+		if err != nil {
+			return err
+		}
+		return drv.ShowNumber(number, leadingZeros)
+	})
+
+	drv.AddCommand("ShowDigits", func(params map[string]interface{}) interface{} {
+		digits, err := stringParam(params, "digits")
+		if err != nil {
+			return err
+		}
+		return drv.ShowDigits(digits)
+	})
+
+	drv.AddCommand("Colon", func(params map[string]interface{}) interface{} {
+		on, err := boolParamOr(params, "on", true)
+		if err != nil {
+			return err
+		}
+		return drv.Colon(on)
+	})
+
+	drv.AddCommand("Brightness", func(params map[string]interface{}) interface{} {
+		brightness, err := intParam(params, "brightness", 0, FourDigitMaxBrightness)
+		if err != nil {
+			return err
+		}
+		return drv.Brightness(byte(brightness))
+	})
+
+	drv.AddCommand("Clock", func(params map[string]interface{}) interface{} {
+		on, err := boolParamOr(params, "on", true)
+		if err != nil {
+			return err
+		}
+		return drv.Clock(on)
+	})
+
+	drv.AddCommand("Scroll", func(params map[string]interface{}) interface{} {
+		text, err := stringParam(params, "text")
+		if err != nil {
+			return err
+		}
+		interval, err := intParamOr(params, "interval", int(DefaultScrollInterval/time.Millisecond), 1, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		loop, err := boolParamOr(params, "loop", false)
+		if err != nil {
+			return err
+		}
+		return drv.Scroll(text, time.Duration(interval)*time.Millisecond, loop)
+	})
+
+	drv.AddCommand("Clear", func(params map[string]interface{}) interface{} {
+		return drv.Clear()
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *Grove4DigitDisplayDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *Grove4DigitDisplayDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *Grove4DigitDisplayDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Start initializes the display
+func (d *Grove4DigitDisplayDriver) Start() (err error) {
+	if err = d.grovepi.FourDigitInit(d.pin); err != nil {
+		return err
+	}
+	if err = d.grovepi.FourDigitBrightness(d.pin, d.brightness); err != nil {
+		return err
+	}
+	if d.clockMode {
+		return d.Clock(true)
+	}
+	return
+}
+
+// Halt stops the clock mode or scrolling and clears the display
+func (d *Grove4DigitDisplayDriver) Halt() (err error) {
+	d.stop()
+	return d.Clear()
+}
+
+// SetBrightness sets the brightness applied on start, 0 to 7
+func (d *Grove4DigitDisplayDriver) SetBrightness(brightness byte) { d.brightness = brightness }
+
+// SetClockMode sets whether the display starts in the clock mode
+func (d *Grove4DigitDisplayDriver) SetClockMode(on bool) { d.clockMode = on }
+
+// Brightness changes the display brightness, 0 to 7
+func (d *Grove4DigitDisplayDriver) Brightness(brightness byte) error {
+	if err := d.grovepi.FourDigitBrightness(d.pin, brightness); err != nil {
+		return err
+	}
+	d.brightness = brightness
+	return nil
+}
+
+// ShowNumber shows the decimal number, -999 to 9999, right aligned
+func (d *Grove4DigitDisplayDriver) ShowNumber(number int, leadingZeros bool) error {
+	if number < -999 || number > 9999 {
+		return fmt.Errorf("%w: number %d", ErrorOutOfRange, number)
+	}
+	d.stop()
+
+	var segments [fourDigitPosition]byte
+	n := number
+	if n < 0 {
+		n = -n
+	}
+	i := fourDigitPosition - 1
+	for ; i >= 0; i-- {
+		segments[i] = fourDigitSegments[n%10]
+		n /= 10
+		if n == 0 {
+			break
+		}
+	}
+	for i--; i >= 0; i-- {
+		if leadingZeros && (number >= 0 || i > 0) {
+			segments[i] = fourDigitSegments[0]
+		}
+	}
+	if number < 0 {
+		segments[firstLit(segments)-1] = fourDigitMinus
+	}
+
+	d.mutex.Lock()
+	colon := d.colon
+	d.mutex.Unlock()
+
+	return d.show(segments, colon)
+}
+
+// ShowDigits shows up to four hexadecimal digits, '-' or ' ', a ':' between the second and third digit lights the colon
+func (d *Grove4DigitDisplayDriver) ShowDigits(digits string) error {
+	d.stop()
+	return d.showDigits(digits)
+}
+
+// Colon lights or clears the colon
+func (d *Grove4DigitDisplayDriver) Colon(on bool) error {
+	d.mutex.Lock()
+	segments := d.segments
+	d.mutex.Unlock()
+
+	return d.show(segments, on)
+}
+
+// Clear clears the display
+func (d *Grove4DigitDisplayDriver) Clear() error {
+	if err := d.grovepi.FourDigitAll(d.pin, false); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.segments = [fourDigitPosition]byte{}
+	d.colon = false
+	return nil
+}
+
+// Clock turns the clock mode on or off, the clock shows local time as HH:MM with blinking colon
+func (d *Grove4DigitDisplayDriver) Clock(on bool) error {
+	d.stop()
+	if !on {
+		return nil
+	}
+
+	d.animate(clockInterval, func(step int) bool {
+		layout := "1504"
+		if step%2 == 0 {
+			layout = "15:04"
+		}
+		_ = d.showDigits(time.Now().Format(layout))
+		return false
+	})
+	return nil
+}
+
+// Scroll scrolls the text from right to left through the display, the text is made of the characters
+// ShowDigits shows. The display is left blank after the text unless it loops until stopped
+func (d *Grove4DigitDisplayDriver) Scroll(text string, interval time.Duration, loop bool) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: scroll interval %v", ErrorOutOfRange, interval)
+	}
+	if _, err := digitSegments(text); err != nil {
+		return err
+	}
+	d.stop()
+
+	blank := strings.Repeat(" ", fourDigitPosition)
+	frames := blank + text + blank
+	positions := len(frames) - fourDigitPosition + 1
+	d.animate(interval, func(step int) bool {
+		i := step % positions
+		if err := d.showDigits(frames[i : i+fourDigitPosition]); err != nil {
+			return true
+		}
+		return !loop && i == positions-1
+	})
+	return nil
+}
+
+// animate shows the frame every interval in background until it reports the end or the animation is stopped
+func (d *Grove4DigitDisplayDriver) animate(interval time.Duration, frame func(step int) bool) {
+	d.mutex.Lock()
+	halt, done := make(chan bool), make(chan struct{})
+	d.animation, d.done = halt, done
+	d.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		for step := 0; ; step++ {
+			if frame(step) {
+				return
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-halt:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the clock or scrolling and waits until its last frame is shown
+func (d *Grove4DigitDisplayDriver) stop() {
+	d.mutex.Lock()
+	halt, done := d.animation, d.done
+	d.animation, d.done = nil, nil
+	d.mutex.Unlock()
+
+	if halt != nil {
+		close(halt)
+		<-done
+	}
+}
+
+func (d *Grove4DigitDisplayDriver) showDigits(digits string) error {
+	colon := false
+	if i := strings.Index(digits, ":"); i >= 0 {
+		colon = true
+		digits = digits[:i] + digits[i+1:]
+	}
+	if len(digits) > fourDigitPosition {
+		return fmt.Errorf("%w: %q has more than %d digits", ErrorOutOfRange, digits, fourDigitPosition)
+	}
+	digitsSegments, err := digitSegments(digits)
+	if err != nil {
+		return err
+	}
+
+	var segments [fourDigitPosition]byte
+	copy(segments[fourDigitPosition-len(digitsSegments):], digitsSegments)
+	return d.show(segments, colon)
+}
+
+// digitSegments returns the segments of hexadecimal digits, '-' and ' '
+func digitSegments(digits string) ([]byte, error) {
+	segments := make([]byte, 0, len(digits))
+	for _, c := range strings.ToLower(digits) {
+		switch {
+		case c >= '0' && c <= '9':
+			segments = append(segments, fourDigitSegments[c-'0'])
+		case c >= 'a' && c <= 'f':
+			segments = append(segments, fourDigitSegments[c-'a'+10])
+		case c == '-':
+			segments = append(segments, fourDigitMinus)
+		case c == ' ':
+			segments = append(segments, 0)
+		default:
+			return nil, fmt.Errorf("%w: %q can't be shown", ErrorOutOfRange, c)
+		}
+	}
+	return segments, nil
+}
+
+// show writes the segments of every position
+func (d *Grove4DigitDisplayDriver) show(segments [fourDigitPosition]byte, colon bool) error {
+	for i, s := range segments {
+		if i == 1 && colon {
+			s |= fourDigitColon
+		}
+		if err := d.grovepi.FourDigitSegment(d.pin, byte(i), s); err != nil {
+			return err
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.segments = segments
+	d.colon = colon
+	return nil
+}
+
+func firstLit(segments [fourDigitPosition]byte) int {
+	for i, s := range segments {
+		if s != 0 {
+			return i
+		}
+	}
+	return fourDigitPosition
+}
@@ -0,0 +1,143 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func writtenSegments(c *fakeConnection) []byte {
+	return segmentsOf(c.written)
+}
+
+func segmentsOf(written [][]byte) []byte {
+	segments := make([]byte, 0)
+	for _, w := range written {
+		if w[0] == CommandFourDigitSegment {
+			segments = append(segments, w[3])
+		}
+	}
+	return segments
+}
+
+func TestGrove4DigitDisplayShowNumber(t *testing.T) {
+	tests := []struct {
+		number       int
+		leadingZeros bool
+		expected     []byte
+	}{
+		{42, false, []byte{0, 0, 0x66, 0x5b}},
+		{42, true, []byte{0x3f, 0x3f, 0x66, 0x5b}},
+		{-5, false, []byte{0, 0, fourDigitMinus, 0x6d}},
+		{-5, true, []byte{fourDigitMinus, 0x3f, 0x3f, 0x6d}},
+		{0, false, []byte{0, 0, 0, 0x3f}},
+	}
+	for _, tt := range tests {
+		gp, connector := newTestGrovePiDriver()
+		d := NewGrove4DigitDisplayDriver(gp, "D5")
+		if err := d.ShowNumber(tt.number, tt.leadingZeros); err != nil {
+			t.Fatal(err)
+		}
+		if s := writtenSegments(connector.connection); string(s) != string(tt.expected) {
+			t.Errorf("%d: expected segments %v, got %v", tt.number, tt.expected, s)
+		}
+	}
+}
+
+func TestGrove4DigitDisplayShowDigitsWithColon(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGrove4DigitDisplayDriver(gp, "D5")
+	if err := d.ShowDigits("12:3f"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x06, 0x5b | fourDigitColon, 0x4f, 0x71}
+	if s := writtenSegments(connector.connection); string(s) != string(expected) {
+		t.Errorf("expected segments %v, got %v", expected, s)
+	}
+	if err := d.ShowDigits("12345"); err == nil {
+		t.Error("expected error for too many digits")
+	}
+}
+
+func newTest4DigitDisplay(t *testing.T) (*Grove4DigitDisplayDriver, *lockedConnection) {
+	connection := &lockedConnection{fakeConnection: &fakeConnection{}}
+	gp := NewGrovePiDriver(&fakeConnector{connection: connection.fakeConnection})
+	if err := gp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	gp.connection = connection
+	return NewGrove4DigitDisplayDriver(gp, "D5"), connection
+}
+
+func TestGrove4DigitDisplayScroll(t *testing.T) {
+	d, connection := newTest4DigitDisplay(t)
+	one, two := fourDigitSegments[1], fourDigitSegments[2]
+	expected := []byte{
+		0, 0, 0, 0,
+		0, 0, 0, one,
+		0, 0, one, two,
+		0, one, two, 0,
+		one, two, 0, 0,
+		two, 0, 0, 0,
+		0, 0, 0, 0,
+	}
+
+	if err := d.Scroll("12", time.Millisecond, false); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(segmentsOf(connection.writes())) < len(expected) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if s := segmentsOf(connection.writes()); string(s) != string(expected) {
+		t.Errorf("expected scrolled segments %v, got %v", expected, s)
+	}
+
+	if err := d.Scroll("12g", time.Millisecond, false); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected invalid character error, got %v", err)
+	}
+}
+
+func TestGrove4DigitDisplayHaltStopsClock(t *testing.T) {
+	d, connection := newTest4DigitDisplay(t)
+
+	if err := d.Clock(true); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	n := len(connection.writes())
+
+	time.Sleep(clockInterval + 50*time.Millisecond)
+	if written := connection.writes(); len(written) != n || written[n-1][0] != CommandFourDigitAllOff {
+		t.Errorf("no clock tick should follow the halt, got %v after %d commands", written[n-1:], n)
+	}
+}
+
+func TestGrove4DigitDisplayBrightnessParam(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGrove4DigitDisplayDriver(gp, "D5")
+
+	if err, _ := d.Command("Scroll")(map[string]interface{}{"text": "12", "interval": 1e300}).(error); !errors.Is(err, ErrorInvalidParam) {
+		t.Errorf("expected invalid scroll interval, got %v", err)
+	}
+	for _, brightness := range []float64{263, 8, -1, 7.5} {
+		err, _ := d.Command("Brightness")(map[string]interface{}{"brightness": brightness}).(error)
+		if !errors.Is(err, ErrorInvalidParam) {
+			t.Errorf("%v: expected invalid param, got %v", brightness, err)
+		}
+	}
+	if n := len(connector.connection.written); n != 0 {
+		t.Errorf("invalid brightness shouldn't be written, got %d writes", n)
+	}
+
+	if err := d.Command("Brightness")(map[string]interface{}{"brightness": 5.0}); err != nil {
+		t.Fatal(err)
+	}
+	if w := connector.connection.written; len(w) != 1 || w[0][0] != CommandFourDigitBrightness || w[0][2] != 5 {
+		t.Errorf("expected brightness 5, got %v", w)
+	}
+}
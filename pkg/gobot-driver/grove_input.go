@@ -0,0 +1,112 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Input modes of the digital input drivers
+const (
+	// InputModePolling reads the pin level every sampling interval, changes between the reads are lost
+	InputModePolling = "polling"
+	// InputModeLatched reads the edges latched by the firmware interrupt handler, no change is lost
+	InputModeLatched = "latched"
+)
+
+// maxLatchedEdges limits the number of edges replayed after a single read
+const maxLatchedEdges = 64
+
+// ErrorInputMode is returned for unknown input mode
+var ErrorInputMode = errors.New("unknown input mode")
+
+// inputReader reads level changes of the digital pin
+type inputReader interface {
+	start() error
+	// changes returns the pin levels after each change since the previous call, oldest first.
+	// The first call after start returns the active level the pin starts with
+	changes() ([]bool, error)
+	stop() error
+}
+
+func newInputReader(gp *GrovePiDriver, pin, mode string) (inputReader, error) {
+	switch mode {
+	case InputModePolling, "":
+		return &pollingInput{grovepi: gp, pin: pin, last: -1}, nil
+	case InputModeLatched:
+		return &latchedInput{grovepi: gp, pin: pin}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrorInputMode, mode)
+}
+
+// pollingInput compares the current level with the previous one
+type pollingInput struct {
+	grovepi *GrovePiDriver
+	pin     string
+	last    int
+}
+
+func (r *pollingInput) start() error {
+	r.last = -1
+	return nil
+}
+
+func (r *pollingInput) changes() ([]bool, error) {
+	level, err := r.grovepi.DigitalRead(r.pin)
+	if err != nil {
+		return nil, err
+	}
+	last := r.last
+	r.last = level
+	if level == last || (last == -1 && level == 0) {
+		return nil, nil
+	}
+	return []bool{level == 1}, nil
+}
+
+func (r *pollingInput) stop() error { return nil }
+
+// latchedInput replays the edges counted by the firmware interrupt handler
+type latchedInput struct {
+	grovepi *GrovePiDriver
+	pin     string
+	active  bool
+}
+
+func (r *latchedInput) start() error {
+	if err := r.grovepi.ISRSet(r.pin, ISRChange); err != nil {
+		return err
+	}
+	// drop edges latched before the start, the active level is reported by the first changes
+	level, _, err := r.grovepi.ISRRead(r.pin)
+	r.active = err == nil && level == 1
+	return err
+}
+
+func (r *latchedInput) changes() ([]bool, error) {
+	level, edges, err := r.grovepi.ISRRead(r.pin)
+	if err != nil {
+		return nil, err
+	}
+	levels := replayEdges(level == 1, int(edges))
+	if r.active {
+		r.active = false
+		levels = append([]bool{true}, levels...)
+	}
+	return levels, nil
+}
+
+func (r *latchedInput) stop() error {
+	return r.grovepi.ISRUnset(r.pin)
+}
+
+// replayEdges returns levels after each of n changes ending with the current level
+func replayEdges(level bool, n int) []bool {
+	if n > maxLatchedEdges {
+		n = maxLatchedEdges + n%2
+	}
+	levels := make([]bool, n)
+	for i := range levels {
+		levels[i] = level == ((n-1-i)%2 == 0)
+	}
+	return levels
+}
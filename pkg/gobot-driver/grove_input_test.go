@@ -0,0 +1,91 @@
+package gobot_driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplayEdges(t *testing.T) {
+	cases := []struct {
+		level  bool
+		n      int
+		levels []bool
+	}{
+		{false, 0, []bool{}},
+		{true, 1, []bool{true}},
+		{false, 2, []bool{true, false}},
+		{true, 3, []bool{true, false, true}},
+	}
+	for _, c := range cases {
+		if levels := replayEdges(c.level, c.n); !reflect.DeepEqual(levels, c.levels) {
+			t.Errorf("replayEdges(%v, %d) = %v, want %v", c.level, c.n, levels, c.levels)
+		}
+	}
+
+	if levels := replayEdges(false, 1001); len(levels) != maxLatchedEdges+1 || levels[0] != false {
+		t.Errorf("replayed edges should be limited keeping the parity, got %d", len(levels))
+	}
+}
+
+func TestLatchedInputChanges(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandISRRead, 0, 2, 0})
+	input := &latchedInput{grovepi: d, pin: "4"}
+
+	levels, err := input.changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(levels, []bool{true, false}) {
+		t.Errorf("expected short press, got %v", levels)
+	}
+	if w := connector.connection.written[0]; w[0] != CommandISRRead || w[1] != 4 {
+		t.Errorf("unexpected command %v", w)
+	}
+}
+
+func TestPollingInputInitialLevel(t *testing.T) {
+	d, _ := newTestGrovePiDriver([]byte{CommandReadDigital, 1}, []byte{CommandReadDigital, 1}, []byte{CommandReadDigital, 0})
+	input := &pollingInput{grovepi: d, pin: "4"}
+	if err := input.start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range [][]bool{{true}, nil, {false}} {
+		levels, err := input.changes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(levels, expected) {
+			t.Errorf("poll %d: expected %v, got %v", i, expected, levels)
+		}
+	}
+
+	d, _ = newTestGrovePiDriver([]byte{CommandReadDigital, 0})
+	input = &pollingInput{grovepi: d, pin: "4"}
+	_ = input.start()
+	if levels, _ := input.changes(); levels != nil {
+		t.Errorf("inactive initial level shouldn't be reported, got %v", levels)
+	}
+}
+
+func TestLatchedInputInitialLevel(t *testing.T) {
+	d, _ := newTestGrovePiDriver(
+		[]byte{CommandISRRead, 1, 0, 0},
+		[]byte{CommandISRRead, 0, 1, 0},
+		[]byte{CommandISRRead, 0, 0, 0},
+	)
+	input := &latchedInput{grovepi: d, pin: "4"}
+	if err := input.start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range [][]bool{{true, false}, {}} {
+		levels, err := input.changes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(levels, expected) {
+			t.Errorf("read %d: expected %v, got %v", i, expected, levels)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// IR is the event of received IR code, its data is IRCode
+const IR = "ir"
+
+// ErrorInvalidIRCode is published when received code isn't a valid NEC code
+var ErrorInvalidIRCode = errors.New("invalid NEC code")
+
+// IRCode is the decoded NEC code
+type IRCode struct {
+	Code    uint32 `json:"code"`
+	Address uint16 `json:"address"`
+	Command byte   `json:"command"`
+	Key     string `json:"key,omitempty"`
+}
+
+// GroveIRReceiverDriver represents a Grove IR receiver read through the GrovePi firmware buffer
+type GroveIRReceiverDriver struct {
+	name     string
+	halt     chan bool
+	pin      string
+	interval time.Duration
+	grovepi  *GrovePiDriver
+	mutex    *sync.Mutex
+	keys     map[uint32]string
+	last     IRCode
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveIRReceiverDriver creates new instance of GroveIRReceiverDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the receiver is attached to
+//   pin string - digital pin of the receiver
+//
+// Optional params:
+//   time.Duration - polling interval of the firmware buffer
+//
+func NewGroveIRReceiverDriver(gp *GrovePiDriver, pin string, i ...time.Duration) *GroveIRReceiverDriver {
+	drv := &GroveIRReceiverDriver{
+		name:      gobot.DefaultName("GroveIRReceiver"),
+		halt:      make(chan bool),
+		pin:       pin,
+		interval:  100 * time.Millisecond,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		keys:      map[uint32]string{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(IR)
+	drv.AddEvent(aio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return drv.Last()
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveIRReceiverDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveIRReceiverDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveIRReceiverDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// SetKeys sets key names of the remote, keys are full NEC codes or commands
+func (d *GroveIRReceiverDriver) SetKeys(keys map[uint32]string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.keys = keys
+}
+
+// Start sets the receiver pin and polls the firmware buffer
+func (d *GroveIRReceiverDriver) Start() (err error) {
+	if err = d.grovepi.IRSetPin(d.pin); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			code, ok, err := d.grovepi.IRRead()
+			if err != nil {
+				d.Publish(aio.Error, err)
+			} else if ok {
+				d.receive(code)
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt returns true if devices is halted successfully
+func (d *GroveIRReceiverDriver) Halt() (err error) {
+	d.halt <- true
+	return
+}
+
+// Last returns the last received code
+func (d *GroveIRReceiverDriver) Last() IRCode {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.last
+}
+
+func (d *GroveIRReceiverDriver) receive(code uint32) {
+	ir, err := DecodeNEC(code)
+	if err != nil {
+		d.Publish(aio.Error, err)
+		return
+	}
+
+	d.mutex.Lock()
+	if key, found := d.keys[ir.Code]; found {
+		ir.Key = key
+	} else if key, found := d.keys[uint32(ir.Command)]; found {
+		ir.Key = key
+	}
+	d.last = ir
+	d.mutex.Unlock()
+
+	d.Publish(IR, ir)
+}
+
+// DecodeNEC decodes 32-bit NEC code sent as address, inverted address, command and inverted command.
+// Extended NEC codes use 16-bit address without the inverted byte
+func DecodeNEC(code uint32) (IRCode, error) {
+	addr, naddr := byte(code>>24), byte(code>>16)
+	cmd, ncmd := byte(code>>8), byte(code)
+	if cmd != ^ncmd {
+		return IRCode{}, fmt.Errorf("%w: %08X", ErrorInvalidIRCode, code)
+	}
+
+	address := uint16(addr)
+	if addr != ^naddr {
+		address = uint16(addr)<<8 | uint16(naddr)
+	}
+	return IRCode{Code: code, Address: address, Command: cmd}, nil
+}
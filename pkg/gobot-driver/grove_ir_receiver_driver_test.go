@@ -0,0 +1,43 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeNEC(t *testing.T) {
+	ir, err := DecodeNEC(0x00FF45BA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ir.Address != 0x00 || ir.Command != 0x45 {
+		t.Errorf("unexpected address %X or command %X", ir.Address, ir.Command)
+	}
+
+	ir, err = DecodeNEC(0x12345AA5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ir.Address != 0x1234 || ir.Command != 0x5A {
+		t.Errorf("unexpected extended address %X or command %X", ir.Address, ir.Command)
+	}
+
+	if _, err := DecodeNEC(0x00FF4500); !errors.Is(err, ErrorInvalidIRCode) {
+		t.Errorf("expected invalid code error, got %v", err)
+	}
+}
+
+func TestGroveIRReceiverKeys(t *testing.T) {
+	gp, _ := newTestGrovePiDriver()
+	d := NewGroveIRReceiverDriver(gp, "D3")
+	d.SetKeys(map[uint32]string{0x45: "power", 0x00FF46B9: "up"})
+
+	d.receive(0x00FF45BA)
+	if k := d.Last().Key; k != "power" {
+		t.Errorf("expected power key by command, got %q", k)
+	}
+	d.receive(0x00FF46B9)
+	if k := d.Last().Key; k != "up" {
+		t.Errorf("expected up key by code, got %q", k)
+	}
+}
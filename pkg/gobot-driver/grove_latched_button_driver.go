@@ -0,0 +1,127 @@
+package gobot_driver
+
+import (
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+// GroveLatchedButtonDriver represents a Grove button read through the firmware interrupt handler,
+// short presses between the reads aren't lost. It publishes the gpio.ButtonDriver events
+type GroveLatchedButtonDriver struct {
+	name     string
+	halt     chan bool
+	pin      string
+	interval time.Duration
+	grovepi  *GrovePiDriver
+	input    inputReader
+	mutex    *sync.Mutex
+	active   bool
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGroveLatchedButtonDriver creates new instance of GroveLatchedButtonDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the button is attached to
+//   pin string - digital pin of the button
+//
+// Optional params:
+//   time.Duration - polling interval of the latched edges
+//
+func NewGroveLatchedButtonDriver(gp *GrovePiDriver, pin string, i ...time.Duration) *GroveLatchedButtonDriver {
+	drv := &GroveLatchedButtonDriver{
+		name:      gobot.DefaultName("GroveLatchedButton"),
+		halt:      make(chan bool),
+		pin:       pin,
+		interval:  50 * time.Millisecond,
+		grovepi:   gp,
+		input:     &latchedInput{grovepi: gp, pin: pin},
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(gpio.ButtonPush)
+	drv.AddEvent(gpio.ButtonRelease)
+	drv.AddEvent(gpio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"active": drv.Active()}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveLatchedButtonDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveLatchedButtonDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveLatchedButtonDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Pin returns the pin of the button
+func (d *GroveLatchedButtonDriver) Pin() string { return d.pin }
+
+// Start attaches the interrupt handler and polls the latched edges
+func (d *GroveLatchedButtonDriver) Start() (err error) {
+	if err = d.input.start(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			levels, err := d.input.changes()
+			if err != nil {
+				d.Publish(gpio.Error, err)
+			}
+			for _, level := range levels {
+				d.update(level)
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling and detaches the interrupt handler
+func (d *GroveLatchedButtonDriver) Halt() (err error) {
+	d.halt <- true
+	return d.input.stop()
+}
+
+// Active returns true while the button is pushed
+func (d *GroveLatchedButtonDriver) Active() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.active
+}
+
+func (d *GroveLatchedButtonDriver) update(level bool) {
+	d.mutex.Lock()
+	d.active = level
+	d.mutex.Unlock()
+
+	if level {
+		d.Publish(gpio.ButtonPush, 1)
+	} else {
+		d.Publish(gpio.ButtonRelease, 0)
+	}
+}
@@ -0,0 +1,187 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// LedBarLength is the number of LEDs of the Grove LED Bar
+const LedBarLength = 10
+
+// GroveLedBarDriver represents a Grove LED Bar driven by the GrovePi firmware
+type GroveLedBarDriver struct {
+	name     string
+	pin      string
+	reversed bool
+	grovepi  *GrovePiDriver
+	mutex    *sync.Mutex
+	unbind   func()
+	gobot.Commander
+}
+
+// NewGroveLedBarDriver creates new instance of GroveLedBarDriver
+// Params:
+//   gp GrovePiDriver - GrovePi the LED bar is attached to
+//   pin string - digital pin of the LED bar
+//
+func NewGroveLedBarDriver(gp *GrovePiDriver, pin string) *GroveLedBarDriver {
+	drv := &GroveLedBarDriver{
+		name:      gobot.DefaultName("GroveLedBar"),
+		pin:       pin,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		Commander: gobot.NewCommander(),
+	}
+
+	drv.AddCommand("Level", func(params map[string]interface{}) interface{} {
+		level, err := intParam(params, "level", 0, LedBarLength)
+		if err != nil {
+			return err
+		}
+		return drv.Level(byte(level))
+	})
+
+	drv.AddCommand("SetLed", func(params map[string]interface{}) interface{} {
+		led, err := intParam(params, "led", 1, LedBarLength)
+		if err != nil {
+			return err
+		}
+		on, err := boolParamOr(params, "on", true)
+		if err != nil {
+			return err
+		}
+		return drv.SetLed(byte(led), on)
+	})
+
+	drv.AddCommand("ToggleLed", func(params map[string]interface{}) interface{} {
+		led, err := intParam(params, "led", 1, LedBarLength)
+		if err != nil {
+			return err
+		}
+		return drv.ToggleLed(byte(led))
+	})
+
+	drv.AddCommand("SetBits", func(params map[string]interface{}) interface{} {
+		bits, err := intParam(params, "bits", 0, 1<<LedBarLength-1)
+		if err != nil {
+			return err
+		}
+		return drv.SetBits(uint16(bits))
+	})
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		bits, err := drv.Bits()
+		return map[string]interface{}{"bits": bits, "err": err}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveLedBarDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveLedBarDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveLedBarDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Start initializes the LED bar
+func (d *GroveLedBarDriver) Start() (err error) {
+	return d.grovepi.LedBarInit(d.pin, d.reversed)
+}
+
+// Halt unbinds the LED bar and turns all LEDs off
+func (d *GroveLedBarDriver) Halt() (err error) {
+	d.Unbind()
+	return d.Level(0)
+}
+
+// SetReversed sets the orientation, reversed bar counts from the green LED
+func (d *GroveLedBarDriver) SetReversed(reversed bool) { d.reversed = reversed }
+
+// Level lights the LEDs up to the level, 0 to 10
+func (d *GroveLedBarDriver) Level(level byte) error {
+	return d.grovepi.LedBarLevel(d.pin, level)
+}
+
+// SetLed turns the LED, 1 to 10, on or off
+func (d *GroveLedBarDriver) SetLed(led byte, on bool) error {
+	return d.grovepi.LedBarSetLed(d.pin, led, on)
+}
+
+// ToggleLed toggles the LED, 1 to 10
+func (d *GroveLedBarDriver) ToggleLed(led byte) error {
+	return d.grovepi.LedBarToggleLed(d.pin, led)
+}
+
+// SetBits sets the state of all LEDs, bit 0 is the first LED
+func (d *GroveLedBarDriver) SetBits(bits uint16) error {
+	return d.grovepi.LedBarSetBits(d.pin, bits)
+}
+
+// Bits returns the state of all LEDs, bit 0 is the first LED
+func (d *GroveLedBarDriver) Bits() (uint16, error) {
+	return d.grovepi.LedBarBits(d.pin)
+}
+
+// Bind shows aio.Data events of the source as the bar level, values from min to max are mapped on 0 to 10.
+// Integer and float values are shown, e.g. of analog sensors or the ultrasonic ranger. Previous binding is replaced
+func (d *GroveLedBarDriver) Bind(source gobot.Eventer, min, max int) error {
+	if max <= min {
+		return fmt.Errorf("%w: range %d..%d", ErrorOutOfRange, min, max)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.unbind != nil {
+		d.unbind()
+	}
+
+	last := -1
+	d.unbind = subscribe(source, func(evt *gobot.Event) {
+		if evt.Name != aio.Data {
+			return
+		}
+		value, ok := numeric(evt.Data)
+		if !ok {
+			return
+		}
+		level := levelOf(value, min, max)
+		if level == last {
+			return
+		}
+		if err := d.Level(byte(level)); err == nil {
+			last = level
+		}
+	})
+	return nil
+}
+
+// Unbind stops showing the source data
+func (d *GroveLedBarDriver) Unbind() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.unbind != nil {
+		d.unbind()
+		d.unbind = nil
+	}
+}
+
+// levelOf maps the value from min to max on the bar level, out of range values are clamped
+func levelOf(value float64, min, max int) int {
+	if value <= float64(min) {
+		return 0
+	}
+	if value >= float64(max) {
+		return LedBarLength
+	}
+	return int((value - float64(min)) * LedBarLength / float64(max-min))
+}
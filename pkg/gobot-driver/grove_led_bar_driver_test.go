@@ -0,0 +1,114 @@
+package gobot_driver
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// lockedConnection guards fakeConnection used from event goroutines
+type lockedConnection struct {
+	*fakeConnection
+	mutex sync.Mutex
+}
+
+func (c *lockedConnection) Write(b []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.fakeConnection.Write(b)
+}
+
+func (c *lockedConnection) lastWritten() []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.written) == 0 {
+		return nil
+	}
+	return c.written[len(c.written)-1]
+}
+
+func newTestLedBar(t *testing.T) (*GroveLedBarDriver, *lockedConnection) {
+	connection := &lockedConnection{fakeConnection: &fakeConnection{}}
+	gp := NewGrovePiDriver(&fakeConnector{connection: connection.fakeConnection})
+	if err := gp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	gp.connection = connection
+	return NewGroveLedBarDriver(gp, "D4"), connection
+}
+
+// waitLevel waits for the bar level command and checks the level
+func waitLevel(t *testing.T, connection *lockedConnection, expected byte) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if w := connection.lastWritten(); w != nil && w[0] == CommandLedBarLevel {
+			if w[2] != expected {
+				t.Errorf("expected level %d, got %d", expected, w[2])
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("level wasn't set")
+}
+
+func TestGroveLedBarBind(t *testing.T) {
+	bar, connection := newTestLedBar(t)
+	source := gobot.NewEventer()
+	if err := bar.Bind(source, 0, 1000); err != nil {
+		t.Fatal(err)
+	}
+	defer bar.Unbind()
+
+	source.Publish(aio.Data, 550)
+	waitLevel(t, connection, 5)
+}
+
+func TestGroveLedBarBindRanger(t *testing.T) {
+	bar, connection := newTestLedBar(t)
+	ranger := NewGroveUltrasonicRangerDriver(nil, "D7")
+	if err := bar.Bind(ranger, 0, 100); err != nil {
+		t.Fatal(err)
+	}
+	defer bar.Unbind()
+
+	ranger.Publish(aio.Data, 73)
+	waitLevel(t, connection, 7)
+}
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected int
+	}{{-5, 0}, {0, 0}, {102, 0}, {103, 1}, {512, 5}, {1023, 10}, {2000, 10}}
+	for _, tt := range tests {
+		if l := levelOf(tt.value, 0, 1023); l != tt.expected {
+			t.Errorf("%v: expected %d, got %d", tt.value, tt.expected, l)
+		}
+	}
+}
+
+func TestGroveLedBarCommandParams(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGroveLedBarDriver(gp, "D5")
+
+	for command, params := range map[string]map[string]interface{}{
+		"Level":     {"level": 266.0},
+		"SetLed":    {"led": 0.0},
+		"ToggleLed": {"led": 267.0},
+		"SetBits":   {"bits": 65536.0},
+	} {
+		err, _ := d.Command(command)(params).(error)
+		if !errors.Is(err, ErrorInvalidParam) {
+			t.Errorf("%s: expected invalid param, got %v", command, err)
+		}
+	}
+	if n := len(connector.connection.written); n != 0 {
+		t.Errorf("invalid params shouldn't be written, got %d writes", n)
+	}
+}
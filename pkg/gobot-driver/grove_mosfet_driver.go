@@ -0,0 +1,70 @@
+package gobot_driver
+
+// GroveMOSFETDriver represents a Grove MOSFET switching a DC load with PWM level, 0 is off.
+// It has the same safety limits and events as GroveRelayDriver
+type GroveMOSFETDriver struct {
+	*actuator
+}
+
+// NewGroveMOSFETDriver creates new instance of GroveMOSFETDriver, the load is off by default and on halt
+// Params:
+//   gp GrovePiDriver - GrovePi the MOSFET is attached to
+//   pin string - PWM capable digital pin of the MOSFET
+//
+func NewGroveMOSFETDriver(gp *GrovePiDriver, pin string) *GroveMOSFETDriver {
+	drv := &GroveMOSFETDriver{}
+	drv.actuator = newActuator("GroveMOSFET", gp, pin, func(level byte) error {
+		pinNum, err := parsePin(pin)
+		if err != nil {
+			return err
+		}
+		return gp.WriteAnalog(pinNum, level)
+	})
+
+	drv.AddCommand("On", func(params map[string]interface{}) interface{} {
+		return drv.On()
+	})
+
+	drv.AddCommand("SetLevel", func(params map[string]interface{}) interface{} {
+		level, err := floatParam(params, "level")
+		if err != nil {
+			return err
+		}
+		if level < 0 || level > 255 {
+			return ErrorInvalidParam
+		}
+		return drv.SetLevel(byte(level))
+	})
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"level": drv.Level()}
+	})
+
+	return drv
+}
+
+// SetStates sets the level on start and the fail-safe level on halt
+func (d *GroveMOSFETDriver) SetStates(initial, failSafe byte) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.initial, d.failSafe = initial, failSafe
+}
+
+// On switches the load on at full level
+func (d *GroveMOSFETDriver) On() error {
+	return d.set(255, false)
+}
+
+// SetLevel sets the PWM level, 0 switches the load off
+func (d *GroveMOSFETDriver) SetLevel(level byte) error {
+	return d.set(level, false)
+}
+
+// Level returns the PWM level
+func (d *GroveMOSFETDriver) Level() byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.level
+}
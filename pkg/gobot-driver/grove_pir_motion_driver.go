@@ -0,0 +1,255 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/gpio"
+)
+
+// PIR motion events
+const (
+	PIRMotion   = "motion"
+	PIRNoMotion = "no-motion"
+)
+
+// DefaultPIRWindow is the default window of the motion count
+const DefaultPIRWindow = time.Minute
+
+// GrovePIRMotionDriver represents a Grove PIR motion sensor read by polling or through the firmware interrupt handler.
+// The area is occupied from the first motion until the sensor stays idle for the clear timeout.
+// PIRMotion is published when the area gets occupied and again on motion after the retrigger timeout,
+// PIRNoMotion is published when the area is cleared
+type GrovePIRMotionDriver struct {
+	name       string
+	halt       chan bool
+	pin        string
+	interval   time.Duration
+	grovepi    *GrovePiDriver
+	input      inputReader
+	mutex      *sync.Mutex
+	motion     bool
+	occupied   bool
+	retrigger  time.Duration
+	clear      time.Duration
+	window     time.Duration
+	published  time.Time
+	clearTimer *time.Timer
+	clearSeq   int
+	motions    []time.Time
+	gobot.Eventer
+	gobot.Commander
+}
+
+// NewGrovePIRMotionDriver creates new instance of GrovePIRMotionDriver in polling mode
+// Params:
+//   gp GrovePiDriver - GrovePi the sensor is attached to
+//   pin string - digital pin of the sensor
+//
+// Optional params:
+//   time.Duration - polling interval
+//
+func NewGrovePIRMotionDriver(gp *GrovePiDriver, pin string, i ...time.Duration) *GrovePIRMotionDriver {
+	input, _ := newInputReader(gp, pin, InputModePolling)
+	drv := &GrovePIRMotionDriver{
+		name:      gobot.DefaultName("GrovePIRMotion"),
+		halt:      make(chan bool),
+		pin:       pin,
+		interval:  100 * time.Millisecond,
+		window:    DefaultPIRWindow,
+		grovepi:   gp,
+		input:     input,
+		mutex:     &sync.Mutex{},
+		Eventer:   gobot.NewEventer(),
+		Commander: gobot.NewCommander(),
+	}
+
+	if len(i) > 0 {
+		drv.interval = i[0]
+	}
+
+	drv.AddEvent(PIRMotion)
+	drv.AddEvent(PIRNoMotion)
+	drv.AddEvent(gpio.Error)
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"motion": drv.Motion(), "occupied": drv.Occupied(), "count": drv.Count()}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GrovePIRMotionDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GrovePIRMotionDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GrovePIRMotionDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Pin returns the pin of the sensor
+func (d *GrovePIRMotionDriver) Pin() string { return d.pin }
+
+// SetMode sets the input mode, InputModePolling or InputModeLatched, before the driver is started
+func (d *GrovePIRMotionDriver) SetMode(mode string) error {
+	input, err := newInputReader(d.grovepi, d.pin, mode)
+	if err != nil {
+		return err
+	}
+	d.input = input
+	return nil
+}
+
+// SetTimeouts sets the minimal time between PIRMotion events of the occupied area
+// and the idle time after which the area is cleared
+func (d *GrovePIRMotionDriver) SetTimeouts(retrigger, clear time.Duration) error {
+	if retrigger < 0 || clear < 0 {
+		return fmt.Errorf("%w: timeouts %v, %v", ErrorOutOfRange, retrigger, clear)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.retrigger, d.clear = retrigger, clear
+	return nil
+}
+
+// SetWindow sets the window of the motion count
+func (d *GrovePIRMotionDriver) SetWindow(window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("%w: window %v", ErrorOutOfRange, window)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.window = window
+	return nil
+}
+
+// Start polls the sensor
+func (d *GrovePIRMotionDriver) Start() (err error) {
+	if err = d.input.start(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			levels, err := d.input.changes()
+			if err != nil {
+				d.Publish(gpio.Error, err)
+			}
+			for _, level := range levels {
+				d.update(level)
+			}
+
+			select {
+			case <-time.After(d.interval):
+			case <-d.halt:
+				return
+			}
+		}
+	}()
+
+	return
+}
+
+// Halt stops polling the sensor
+func (d *GrovePIRMotionDriver) Halt() (err error) {
+	d.halt <- true
+
+	d.mutex.Lock()
+	if d.clearTimer != nil {
+		d.clearTimer.Stop()
+		d.clearTimer = nil
+	}
+	d.mutex.Unlock()
+
+	return d.input.stop()
+}
+
+// Motion returns true while the sensor detects motion
+func (d *GrovePIRMotionDriver) Motion() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.motion
+}
+
+// Occupied returns true from the first motion until the area is cleared
+func (d *GrovePIRMotionDriver) Occupied() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.occupied
+}
+
+// Count returns the number of motions detected within the window
+func (d *GrovePIRMotionDriver) Count() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.expireMotions(time.Now())
+	return len(d.motions)
+}
+
+func (d *GrovePIRMotionDriver) update(level bool) {
+	now := time.Now()
+
+	d.mutex.Lock()
+	d.motion = level
+	if !level {
+		if d.occupied && d.clearTimer == nil {
+			d.clearSeq++
+			seq := d.clearSeq
+			d.clearTimer = time.AfterFunc(d.clear, func() { d.clearOccupancy(seq) })
+		}
+		d.mutex.Unlock()
+		return
+	}
+
+	if d.clearTimer != nil {
+		d.clearTimer.Stop()
+		d.clearTimer = nil
+	}
+	d.motions = append(d.motions, now)
+	d.expireMotions(now)
+	publish := !d.occupied || now.Sub(d.published) >= d.retrigger
+	d.occupied = true
+	if publish {
+		d.published = now
+	}
+	d.mutex.Unlock()
+
+	if publish {
+		d.Publish(PIRMotion, 1)
+	}
+}
+
+// clearOccupancy clears the area unless the motion was detected again since the clear timer seq was started
+func (d *GrovePIRMotionDriver) clearOccupancy(seq int) {
+	d.mutex.Lock()
+	if d.motion || !d.occupied || d.clearTimer == nil || seq != d.clearSeq {
+		d.mutex.Unlock()
+		return
+	}
+	d.clearTimer = nil
+	d.occupied = false
+	d.mutex.Unlock()
+
+	d.Publish(PIRNoMotion, 0)
+}
+
+// expireMotions drops motions older than the window, the caller must hold the mutex
+func (d *GrovePIRMotionDriver) expireMotions(now time.Time) {
+	i := 0
+	for i < len(d.motions) && now.Sub(d.motions[i]) >= d.window {
+		i++
+	}
+	d.motions = d.motions[i:]
+}
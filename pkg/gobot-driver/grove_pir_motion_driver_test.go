@@ -0,0 +1,47 @@
+package gobot_driver
+
+import (
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+func TestGrovePIRMotionOccupancy(t *testing.T) {
+	d := NewGrovePIRMotionDriver(nil, "8")
+	if err := d.SetTimeouts(time.Hour, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan string, 10)
+	unsubscribe := subscribe(d, func(evt *gobot.Event) { events <- evt.Name })
+	defer unsubscribe()
+
+	d.update(true)
+	d.update(false)
+	d.update(true)
+	d.update(false)
+	if !d.Occupied() {
+		t.Error("area should stay occupied until the clear timeout")
+	}
+	if n := d.Count(); n != 2 {
+		t.Errorf("expected 2 motions, got %d", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if d.Occupied() {
+		t.Error("area should be cleared after the clear timeout")
+	}
+
+	var names []string
+	for len(events) > 0 || len(names) < 2 {
+		select {
+		case name := <-events:
+			names = append(names, name)
+		case <-time.After(time.Second):
+			t.Fatalf("missing events, got %v", names)
+		}
+	}
+	if len(names) != 2 || names[0] != PIRMotion || names[1] != PIRNoMotion {
+		t.Errorf("retriggered motion should not be published, got %v", names)
+	}
+}
@@ -0,0 +1,54 @@
+package gobot_driver
+
+// GroveRelayDriver represents a Grove relay with safety limits, see SetTimes and SetInterlock.
+// It publishes ActuatorOn, ActuatorOff and ActuatorAutoOff events
+type GroveRelayDriver struct {
+	*actuator
+}
+
+// NewGroveRelayDriver creates new instance of GroveRelayDriver, the relay is off by default and on halt
+// Params:
+//   gp GrovePiDriver - GrovePi the relay is attached to
+//   pin string - digital pin of the relay
+//
+func NewGroveRelayDriver(gp *GrovePiDriver, pin string) *GroveRelayDriver {
+	drv := &GroveRelayDriver{}
+	drv.actuator = newActuator("GroveRelay", gp, pin, func(level byte) error {
+		return gp.DigitalWrite(pin, boolToByte(level > 0))
+	})
+
+	drv.AddCommand("On", func(params map[string]interface{}) interface{} {
+		return drv.On()
+	})
+
+	drv.AddCommand("Toggle", func(params map[string]interface{}) interface{} {
+		return drv.Toggle()
+	})
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"on": drv.IsOn()}
+	})
+
+	return drv
+}
+
+// SetStates sets the state on start and the fail-safe state on halt
+func (d *GroveRelayDriver) SetStates(initial, failSafe bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.initial, d.failSafe = boolToByte(initial), boolToByte(failSafe)
+}
+
+// On switches the relay on
+func (d *GroveRelayDriver) On() error {
+	return d.set(1, false)
+}
+
+// Toggle switches the relay over
+func (d *GroveRelayDriver) Toggle() error {
+	if d.IsOn() {
+		return d.Off()
+	}
+	return d.On()
+}
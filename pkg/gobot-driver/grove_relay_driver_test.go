@@ -0,0 +1,138 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+func TestGroveRelayInterlock(t *testing.T) {
+	gp, _ := newTestGrovePiDriver()
+	heater, cooler := NewGroveRelayDriver(gp, "2"), NewGroveRelayDriver(gp, "3")
+	group := NewInterlock("hvac")
+	if err := heater.SetInterlock(group); err != nil {
+		t.Fatal(err)
+	}
+	if err := cooler.SetInterlock(group); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := heater.On(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cooler.On(); !errors.Is(err, ErrorInterlocked) {
+		t.Errorf("expected interlock error, got %v", err)
+	}
+	if err := heater.Off(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cooler.On(); err != nil {
+		t.Errorf("released interlock should allow the other relay, got %v", err)
+	}
+}
+
+func TestGroveRelayTimes(t *testing.T) {
+	gp, _ := newTestGrovePiDriver()
+	d := NewGroveRelayDriver(gp, "2")
+	if err := d.SetTimes(time.Hour, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.On(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Off(); !errors.Is(err, ErrorMinTime) {
+		t.Errorf("expected minimum on time error, got %v", err)
+	}
+	if err := d.Halt(); err != nil || d.IsOn() {
+		t.Errorf("halt should switch to the fail-safe state, got %v", err)
+	}
+
+	autoOff := make(chan interface{}, 1)
+	unsubscribe := subscribe(d.Eventer, func(evt *gobot.Event) {
+		if evt.Name == ActuatorAutoOff {
+			autoOff <- evt.Data
+		}
+	})
+	defer unsubscribe()
+
+	if err := d.SetTimes(0, 0, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.On(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-autoOff:
+	case <-time.After(time.Second):
+		t.Fatal("auto-off event not published")
+	}
+	if d.IsOn() {
+		t.Error("relay should be switched off after the maximal on duration")
+	}
+}
+
+func TestGroveRelayStaleAutoOff(t *testing.T) {
+	gp, _ := newTestGrovePiDriver()
+	d := NewGroveRelayDriver(gp, "2")
+	if err := d.SetTimes(0, 0, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.On(); err != nil {
+		t.Fatal(err)
+	}
+	d.mutex.Lock()
+	stale := d.seq
+	d.mutex.Unlock()
+	if err := d.Off(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.On(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the timer of the first switching fired before Off stopped it
+	d.expire(stale)
+	if !d.IsOn() {
+		t.Fatal("stale auto-off switched the relay off")
+	}
+
+	d.mutex.Lock()
+	current := d.seq
+	d.mutex.Unlock()
+	d.expire(current)
+	if d.IsOn() {
+		t.Error("relay should be switched off by its own auto-off")
+	}
+}
+
+func TestGroveMOSFETLevel(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGroveMOSFETDriver(gp, "D5")
+
+	if err := d.SetLevel(128); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetLevel(0); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{
+		{CommandPinMode, 5, 1, 0},
+		{CommandWriteAnalog, 5, 128, 0},
+		{CommandWriteAnalog, 5, 0, 0},
+	}
+	if fmt.Sprint(connector.connection.written) != fmt.Sprint(want) {
+		t.Errorf("expected writes %v, got %v", want, connector.connection.written)
+	}
+	if d.Level() != 0 || d.IsOn() {
+		t.Errorf("expected MOSFET off, got level %d", d.Level())
+	}
+
+	if err := NewGroveMOSFETDriver(gp, "D4").On(); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected no PWM error, got %v", err)
+	}
+}
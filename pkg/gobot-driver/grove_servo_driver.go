@@ -0,0 +1,261 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+// servoStep is the period of the speed limited move steps
+const servoStep = 20 * time.Millisecond
+
+// GroveServoDriver represents a servo driven by the GrovePi firmware.
+// Angles are logical, 90 is the center which is mapped on the calibrated physical center
+type GroveServoDriver struct {
+	name     string
+	pin      string
+	grovepi  *GrovePiDriver
+	mutex    *sync.Mutex
+	min, max float64
+	center   float64
+	speed    float64
+	angle    float64
+	known    bool
+	target   float64
+	moving   chan bool
+	done     chan struct{}
+	gobot.Commander
+}
+
+// NewGroveServoDriver creates new instance of GroveServoDriver, the full range moves at full speed
+// Params:
+//   gp GrovePiDriver - GrovePi the servo is attached to
+//   pin string - PWM capable digital pin of the servo
+//
+func NewGroveServoDriver(gp *GrovePiDriver, pin string) *GroveServoDriver {
+	drv := &GroveServoDriver{
+		name:      gobot.DefaultName("GroveServo"),
+		pin:       pin,
+		grovepi:   gp,
+		mutex:     &sync.Mutex{},
+		min:       0,
+		max:       180,
+		center:    90,
+		Commander: gobot.NewCommander(),
+	}
+
+	drv.AddCommand("Move", func(params map[string]interface{}) interface{} {
+		angle, err := floatParam(params, "angle")
+		if err != nil {
+			return err
+		}
+		return drv.Move(angle)
+	})
+
+	drv.AddCommand("Min", func(params map[string]interface{}) interface{} {
+		return drv.Min()
+	})
+
+	drv.AddCommand("Center", func(params map[string]interface{}) interface{} {
+		return drv.Center()
+	})
+
+	drv.AddCommand("Max", func(params map[string]interface{}) interface{} {
+		return drv.Max()
+	})
+
+	drv.AddCommand("Stop", func(params map[string]interface{}) interface{} {
+		drv.Stop()
+		return nil
+	})
+
+	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"angle": drv.Angle(), "target": drv.Target()}
+	})
+
+	return drv
+}
+
+// Name returns the Name for the Driver
+func (d *GroveServoDriver) Name() string { return d.name }
+
+// SetName sets the Name for the Driver
+func (d *GroveServoDriver) SetName(n string) { d.name = n }
+
+// Connection returns the connection for the Driver
+func (d *GroveServoDriver) Connection() gobot.Connection {
+	return d.grovepi.Connection()
+}
+
+// Start implements the Driver interface
+func (d *GroveServoDriver) Start() (err error) { return }
+
+// Halt stops the move and the servo pulses
+func (d *GroveServoDriver) Halt() (err error) {
+	d.Stop()
+	return d.grovepi.ServoDetach(d.pin)
+}
+
+// SetLimits limits logical angles of the moves
+func (d *GroveServoDriver) SetLimits(min, max float64) error {
+	if min < 0 || max > 180 || max < min {
+		return fmt.Errorf("%w: limits %v..%v", ErrorOutOfRange, min, max)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.min, d.max = min, max
+	return nil
+}
+
+// SetCenter calibrates the physical angle of the logical center
+func (d *GroveServoDriver) SetCenter(center float64) error {
+	if center < 0 || center > 180 {
+		return fmt.Errorf("%w: center %v", ErrorOutOfRange, center)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.center = center
+	return nil
+}
+
+// SetSpeed limits the move speed in degrees per second, zero moves at full speed
+func (d *GroveServoDriver) SetSpeed(speed float64) error {
+	if speed < 0 {
+		return fmt.Errorf("%w: speed %v", ErrorOutOfRange, speed)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.speed = speed
+	return nil
+}
+
+// Angle returns the last written logical angle
+func (d *GroveServoDriver) Angle() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.angle
+}
+
+// Target returns the logical angle of the current or last move
+func (d *GroveServoDriver) Target() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.target
+}
+
+// Move moves the servo to the logical angle within the limits. Speed limited move runs in background
+// from the last written angle, the first move after start is done at full speed
+func (d *GroveServoDriver) Move(angle float64) error {
+	d.Stop()
+
+	d.mutex.Lock()
+	if angle < d.min || angle > d.max {
+		d.mutex.Unlock()
+		return fmt.Errorf("%w: angle %v out of %v..%v", ErrorOutOfRange, angle, d.min, d.max)
+	}
+	d.target = angle
+	if d.speed == 0 || !d.known {
+		d.mutex.Unlock()
+		return d.write(angle)
+	}
+	from, step := d.angle, d.speed*servoStep.Seconds()
+	halt, done := make(chan bool), make(chan struct{})
+	d.moving, d.done = halt, done
+	d.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		for position := from; position != angle; {
+			if angle > position {
+				position = math.Min(position+step, angle)
+			} else {
+				position = math.Max(position-step, angle)
+			}
+			if err := d.write(position); err != nil {
+				return
+			}
+
+			select {
+			case <-time.After(servoStep):
+			case <-halt:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Min moves the servo to the lower limit
+func (d *GroveServoDriver) Min() error {
+	d.mutex.Lock()
+	min := d.min
+	d.mutex.Unlock()
+
+	return d.Move(min)
+}
+
+// Center moves the servo to the logical center, or to the closest limit when the limits exclude it
+func (d *GroveServoDriver) Center() error {
+	d.mutex.Lock()
+	center := math.Max(d.min, math.Min(d.max, 90))
+	d.mutex.Unlock()
+
+	return d.Move(center)
+}
+
+// Max moves the servo to the upper limit
+func (d *GroveServoDriver) Max() error {
+	d.mutex.Lock()
+	max := d.max
+	d.mutex.Unlock()
+
+	return d.Move(max)
+}
+
+// Stop stops the running move at the current angle and waits until its last step is written
+func (d *GroveServoDriver) Stop() {
+	d.mutex.Lock()
+	halt, done := d.moving, d.done
+	d.moving, d.done = nil, nil
+	d.mutex.Unlock()
+
+	if halt != nil {
+		close(halt)
+		<-done
+	}
+}
+
+// write sets the logical angle
+func (d *GroveServoDriver) write(angle float64) error {
+	d.mutex.Lock()
+	physical := d.physical(angle)
+	d.mutex.Unlock()
+
+	if err := d.grovepi.ServoWrite(d.pin, physical); err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.angle, d.known = angle, true
+	return nil
+}
+
+// physical maps the logical angle on the calibrated servo angle, the caller must hold the mutex
+func (d *GroveServoDriver) physical(angle float64) byte {
+	return byte(math.Round(math.Max(0, math.Min(180, angle-90+d.center))))
+}
@@ -0,0 +1,89 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroveServoCalibration(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGroveServoDriver(gp, "5")
+	if err := d.SetLimits(30, 150); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetCenter(100); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Center(); err != nil {
+		t.Fatal(err)
+	}
+	written := connector.connection.written
+	if w := written[len(written)-1]; w[0] != CommandServoWrite || w[1] != 5 || w[2] != 100 {
+		t.Errorf("expected calibrated center 100, got %v", w)
+	}
+
+	if err := d.Move(20); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	if err := d.Move(150); err != nil {
+		t.Fatal(err)
+	}
+	written = connector.connection.written
+	if w := written[len(written)-1]; w[2] != 160 {
+		t.Errorf("expected physical angle 160, got %d", w[2])
+	}
+	if angle := d.Angle(); angle != 150 {
+		t.Errorf("expected angle 150, got %v", angle)
+	}
+}
+
+func TestGroveServoCenterWithinLimits(t *testing.T) {
+	gp, connector := newTestGrovePiDriver()
+	d := NewGroveServoDriver(gp, "5")
+	if err := d.SetLimits(100, 170); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Center(); err != nil {
+		t.Fatal(err)
+	}
+	written := connector.connection.written
+	if w := written[len(written)-1]; w[2] != 100 || d.Angle() != 100 {
+		t.Errorf("expected center clamped to the lower limit 100, got %v", w)
+	}
+}
+
+func TestGroveServoHaltStopsMove(t *testing.T) {
+	connection := &lockedConnection{fakeConnection: &fakeConnection{}}
+	gp := NewGrovePiDriver(&fakeConnector{connection: connection.fakeConnection})
+	if err := gp.Start(); err != nil {
+		t.Fatal(err)
+	}
+	gp.connection = connection
+
+	d := NewGroveServoDriver(gp, "5")
+	if err := d.Move(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSpeed(100); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Move(180); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * servoStep)
+	if err := d.Halt(); err != nil {
+		t.Fatal(err)
+	}
+	n := len(connection.writes())
+
+	time.Sleep(3 * servoStep)
+	if written := connection.writes(); len(written) != n || written[n-1][0] != CommandServoDetach {
+		t.Errorf("no step should follow the halt, got %v after %d commands", written[n-1:], n)
+	}
+	if angle := d.Angle(); angle <= 0 || angle >= 180 {
+		t.Errorf("move should stop halfway, got %v", angle)
+	}
+}
@@ -1,20 +1,46 @@
 package gobot_driver
 
 import (
+	"errors"
+	"fmt"
 	"gobot.io/x/gobot"
 	"gobot.io/x/gobot/drivers/aio"
+	"math"
+	"sync"
 	"time"
 )
 
+// Distance units
+const (
+	Centimeters = "cm"
+	Millimeters = "mm"
+	Inches      = "in"
+)
+
+// Distance is the event of the distance in the driver unit published next to aio.Data, its data is float64
+const Distance = "distance"
+
+// firmwareSoundSpeed is the speed of sound in m/s the firmware converts the echo time with, 29 µs/cm
+const firmwareSoundSpeed = 10000.0 / 29
+
+// TemperatureSource returns the current air temperature in Celsius degrees, ok is false when it's unknown
+type TemperatureSource func() (celsius float64, ok bool)
+
 // GroveUltrasonicRangerDriver represents a Grove ultrasonic ranger Sensor
-// Distance is reported in centimeters
+// aio.Data events carry whole centimeters, Distance events carry the distance in the driver unit
 type GroveUltrasonicRangerDriver struct {
-	name     string
-	halt     chan bool
-	pin      string
-	distance int
-	interval time.Duration
-	grovepi  *GrovePiDriver
+	name        string
+	halt        chan bool
+	pin         string
+	mutex       *sync.Mutex
+	distance    float64
+	interval    time.Duration
+	grovepi     *GrovePiDriver
+	filters     FilterChain
+	policy      publisher
+	unit        string
+	limits      *Range
+	temperature TemperatureSource
 	gobot.Eventer
 	gobot.Commander
 }
@@ -32,8 +58,11 @@ func NewGroveUltrasonicRangerDriver(gp *GrovePiDriver, pin string, i ...time.Dur
 		name:      gobot.DefaultName("GroveUltrasonicRanger"),
 		halt:      make(chan bool),
 		pin:       pin,
+		mutex:     &sync.Mutex{},
 		grovepi:   gp,
 		interval:  10 * time.Millisecond,
+		policy:    publisher{policy: DefaultPublishPolicy},
+		unit:      Centimeters,
 		Eventer:   gobot.NewEventer(),
 		Commander: gobot.NewCommander(),
 	}
@@ -43,6 +72,7 @@ func NewGroveUltrasonicRangerDriver(gp *GrovePiDriver, pin string, i ...time.Dur
 	}
 
 	drv.AddEvent(aio.Data)
+	drv.AddEvent(Distance)
 	drv.AddEvent(aio.Error)
 
 	drv.AddCommand("Read", func(params map[string]interface{}) interface{} {
@@ -50,6 +80,10 @@ func NewGroveUltrasonicRangerDriver(gp *GrovePiDriver, pin string, i ...time.Dur
 		return map[string]interface{}{"val": val, "err": err}
 	})
 
+	drv.AddCommand("Distance", func(params map[string]interface{}) interface{} {
+		return map[string]interface{}{"distance": drv.PreciseDistance(), "unit": drv.Unit()}
+	})
+
 	return drv
 }
 
@@ -67,17 +101,22 @@ func (d *GroveUltrasonicRangerDriver) Connection() gobot.Connection {
 // Start initialized the GrovePi
 func (d *GroveUltrasonicRangerDriver) Start() (err error) {
 
+	d.mutex.Lock()
 	d.distance = 0
+	d.mutex.Unlock()
+	d.filters.Reset()
+	d.policy.reset()
 
 	go func() {
 		for {
 			newValue, err := d.Read()
 
-			if err != nil {
+			if errors.Is(err, ErrorNoEcho) {
+				// nothing in range, the last distance is kept
+			} else if err != nil {
 				d.Publish(aio.Error, err)
-			} else if newValue != d.distance && d.distance != -1 {
-				d.distance = newValue
-				d.Publish(aio.Data, d.distance)
+			} else {
+				d.sample(newValue, time.Now())
 			}
 
 			select {
@@ -91,13 +130,97 @@ func (d *GroveUltrasonicRangerDriver) Start() (err error) {
 	return
 }
 
+// sample converts and filters a reading and publishes it when the publish policy selects it
+func (d *GroveUltrasonicRangerDriver) sample(cm int, now time.Time) {
+	distance, err := d.convert(cm)
+	if err != nil {
+		d.Publish(aio.Error, err)
+		return
+	}
+	filtered, ok := d.filters.Apply(distance)
+	if !ok {
+		return
+	}
+	filtered = math.Round(filtered*10) / 10
+	d.mutex.Lock()
+	d.distance = filtered
+	unit := d.unit
+	d.mutex.Unlock()
+	if d.policy.publish(filtered, now) {
+		factor, _ := convertDistance(1, unit)
+		d.Publish(aio.Data, int(math.Round(filtered/factor)))
+		d.Publish(Distance, filtered)
+	}
+}
+
+// SetFilters sets the filters applied to the distance before it is published
+func (d *GroveUltrasonicRangerDriver) SetFilters(filters FilterChain) { d.filters = filters }
+
+// SetPublishPolicy sets which distance samples are published
+func (d *GroveUltrasonicRangerDriver) SetPublishPolicy(p PublishPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	d.policy = publisher{policy: p}
+	return nil
+}
+
+// SetUnit sets the unit of the distance, centimeters, millimeters or inches
+func (d *GroveUltrasonicRangerDriver) SetUnit(unit string) error {
+	if _, err := convertDistance(0, unit); err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.unit = unit
+	return nil
+}
+
+// Unit returns the unit of the distance
+func (d *GroveUltrasonicRangerDriver) Unit() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.unit
+}
+
+// SetRange sets the valid distance range in the driver unit, readings out of it are published as errors
+func (d *GroveUltrasonicRangerDriver) SetRange(min, max float64) error {
+	if max < min {
+		return fmt.Errorf("%w: range %v..%v", ErrorOutOfRange, min, max)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.limits = &Range{Min: min, Max: max}
+	return nil
+}
+
+// SetTemperatureSource sets the air temperature the speed of sound is compensated with, nil disables the compensation
+func (d *GroveUltrasonicRangerDriver) SetTemperatureSource(source TemperatureSource) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.temperature = source
+}
+
 // Halt returns true if devices is halted successfully
 func (d *GroveUltrasonicRangerDriver) Halt() (err error) {
 	d.halt <- true
 	return
 }
 
+// Distance returns the last distance rounded to whole driver units, centimeters by default
 func (d *GroveUltrasonicRangerDriver) Distance() int {
+	return int(math.Round(d.PreciseDistance()))
+}
+
+// PreciseDistance returns the last distance in the driver unit with one decimal place
+func (d *GroveUltrasonicRangerDriver) PreciseDistance() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	return d.distance
 }
 
@@ -105,3 +228,43 @@ func (d *GroveUltrasonicRangerDriver) Distance() int {
 func (d *GroveUltrasonicRangerDriver) Read() (val int, err error) {
 	return d.grovepi.UltrasonicRead(d.pin)
 }
+
+// convert compensates the firmware distance in centimeters and converts it to the driver unit
+func (d *GroveUltrasonicRangerDriver) convert(cm int) (float64, error) {
+	d.mutex.Lock()
+	unit, limits, temperature := d.unit, d.limits, d.temperature
+	d.mutex.Unlock()
+
+	distance := float64(cm)
+	if temperature != nil {
+		if celsius, ok := temperature(); ok {
+			distance *= SoundSpeed(celsius) / firmwareSoundSpeed
+		}
+	}
+	distance, err := convertDistance(distance, unit)
+	if err != nil {
+		return 0, err
+	}
+	if limits != nil && !limits.Contains(distance) {
+		return 0, fmt.Errorf("%w: %s distance %.1f%s out of %v..%v", ErrorOutOfRange, d.name, distance, unit, limits.Min, limits.Max)
+	}
+	return distance, nil
+}
+
+// SoundSpeed returns the speed of sound in dry air in m/s
+func SoundSpeed(celsius float64) float64 {
+	return 331.3 * math.Sqrt(1+celsius/273.15)
+}
+
+// convertDistance converts the distance in centimeters to the unit
+func convertDistance(cm float64, unit string) (float64, error) {
+	switch unit {
+	case Centimeters:
+		return cm, nil
+	case Millimeters:
+		return cm * 10, nil
+	case Inches:
+		return cm / 2.54, nil
+	}
+	return 0, fmt.Errorf("%w: distance unit %q", ErrorOutOfRange, unit)
+}
@@ -0,0 +1,102 @@
+package gobot_driver
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+func TestGrovePiDriverUltrasonicRead(t *testing.T) {
+	d, _ := newTestGrovePiDriver([]byte{CommandPinMode}, []byte{CommandReadUltrasonic, 1, 44})
+	val, err := d.UltrasonicRead("D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 300 {
+		t.Errorf("expected 1*256 + 44 = 300, got %d", val)
+	}
+
+}
+
+func TestGrovePiDriverUltrasonicNoEcho(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandPinMode}, []byte{CommandReadUltrasonic, 0xFF, 0xFF})
+	resets := 0
+	d.SetResetFunc(func() error {
+		resets++
+		return nil
+	})
+
+	polls := DefaultResetThreshold + 1
+	for i := 0; i < polls; i++ {
+		if _, err := d.UltrasonicRead("D4"); !errors.Is(err, ErrorNoEcho) {
+			t.Fatalf("expected no echo error, got %v", err)
+		}
+	}
+	if resets != 0 {
+		t.Errorf("no echo shouldn't reset the board, got %d resets", resets)
+	}
+	if n := len(connector.connection.written); n != polls+1 {
+		t.Errorf("no echo shouldn't be retried, expected %d commands, got %d", polls+1, n)
+	}
+}
+
+func TestGroveUltrasonicRangerConvert(t *testing.T) {
+	d := NewGroveUltrasonicRangerDriver(nil, "D4")
+	if err := d.SetUnit(Millimeters); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.convert(100); err != nil || v != 1000 {
+		t.Errorf("expected 1000mm, got %v, %v", v, err)
+	}
+
+	d.SetTemperatureSource(func() (float64, bool) { return 0, true })
+	if v, _ := d.convert(100); math.Abs(v-960.8) > 0.5 {
+		t.Errorf("expected shorter distance in cold air, got %vmm", v)
+	}
+	d.SetTemperatureSource(func() (float64, bool) { return 0, false })
+	if v, _ := d.convert(100); v != 1000 {
+		t.Errorf("unknown temperature shouldn't compensate, got %vmm", v)
+	}
+
+	_ = d.SetUnit(Inches)
+	_ = d.SetRange(2, 100)
+	if v, err := d.convert(254); err != nil || math.Abs(v-100) > 1e-9 {
+		t.Errorf("expected 100in, got %v, %v", v, err)
+	}
+	if _, err := d.convert(300); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+	if err := d.SetUnit("ft"); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected unknown unit error, got %v", err)
+	}
+}
+
+func TestGroveUltrasonicRangerEvents(t *testing.T) {
+	d := NewGroveUltrasonicRangerDriver(nil, "D4")
+	if err := d.SetUnit(Millimeters); err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan *gobot.Event, 10)
+	unsubscribe := subscribe(d, func(evt *gobot.Event) { events <- evt })
+	defer unsubscribe()
+
+	d.SetTemperatureSource(func() (float64, bool) { return 0, true })
+	d.sample(100, time.Now())
+
+	expected := map[string]interface{}{aio.Data: 96, Distance: 960.8}
+	for len(expected) > 0 {
+		select {
+		case evt := <-events:
+			if data, found := expected[evt.Name]; !found || evt.Data != data {
+				t.Errorf("unexpected %s event %v (%T)", evt.Name, evt.Data, evt.Data)
+			}
+			delete(expected, evt.Name)
+		case <-time.After(time.Second):
+			t.Fatalf("missing events %v", expected)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package gobot_driver
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,7 +14,65 @@ import (
 	"gobot.io/x/gobot/drivers/i2c"
 )
 
-const grovePiAddress = 0x04
+// DefaultAddress is the I2C address of the GrovePi firmware
+const DefaultAddress = 0x04
+
+const (
+	// counterNotReady marks disabled firmware counter
+	counterNotReady = 0xFF
+
+	// counterReady marks new firmware counter sample
+	counterReady = 1
+
+	// irNoCode marks empty IR receiver buffer
+	irNoCode = 0xFF
+
+	// irResponseLen is the echo byte followed by the IRSendRev receive buffer
+	irResponseLen = 21
+
+	// irDataLen and irData are offsets of the data length and the data bytes in the response,
+	// the IRSendRev buffer starts with the length and the start, short and long pulse times
+	irDataLen = 6
+	irData    = 7
+
+	// necCodeLen is the number of data bytes of the NEC code
+	necCodeLen = 4
+
+	// ultrasonicNoEcho is the distance of the ultrasonic ranger when the echo timed out
+	ultrasonicNoEcho = 0xFFFF
+
+	// echoNotReady is the echo byte of the firmware buffer while the command is still in progress
+	echoNotReady = 0xFF
+)
+
+var errNotConnected = errors.New("not connected")
+
+// Edges latched by the firmware interrupt handler
+const (
+	ISRRising  = 1
+	ISRFalling = 2
+	ISRChange  = 3
+)
+
+// DHT module types of the DHT read command
+const (
+	DHT11  = 0 // blue module
+	DHT22  = 1 // white module, also sold as AM2302
+	DHT21  = 2
+	AM2301 = 3
+)
+
+// dhtRange is the physical range of the DHT module readings
+type dhtRange struct {
+	temperature, humidity Range
+}
+
+var dhtRanges = map[byte]dhtRange{
+	DHT11:  {Range{0, 50}, Range{0, 100}},
+	DHT22:  {Range{-40, 80}, Range{0, 100}},
+	DHT21:  {Range{-40, 80}, Range{0, 100}},
+	AM2301: {Range{-40, 80}, Range{0, 100}},
+}
 
 // Commands format
 const (
@@ -22,6 +83,53 @@ const (
 	CommandPinMode        = 5
 	CommandReadUltrasonic = 7
 	CommandReadDHT        = 40
+
+	CommandISRSet   = 6
+	CommandISRRead  = 9
+	CommandISRUnset = 19
+
+	CommandDustSensorRead    = 10
+	CommandDustSensorEnable  = 14
+	CommandDustSensorDisable = 15
+
+	CommandEncoderRead    = 11
+	CommandEncoderEnable  = 16
+	CommandEncoderDisable = 17
+
+	CommandFlowRead    = 12
+	CommandFlowDisable = 13
+	CommandFlowEnable  = 18
+
+	CommandServoWrite  = 60
+	CommandServoDetach = 61
+
+	CommandIRRead   = 21
+	CommandIRSetPin = 22
+
+	CommandLedBarInit        = 50
+	CommandLedBarOrientation = 51
+	CommandLedBarLevel       = 52
+	CommandLedBarSetLed      = 53
+	CommandLedBarToggleLed   = 54
+	CommandLedBarSetBits     = 55
+	CommandLedBarGetBits     = 56
+
+	CommandChainableRGBStoreColor = 90
+	CommandChainableRGBInit       = 91
+	CommandChainableRGBTest       = 92
+	CommandChainableRGBPattern    = 93
+	CommandChainableRGBModulo     = 94
+	CommandChainableRGBLevel      = 95
+
+	CommandFourDigitInit       = 70
+	CommandFourDigitBrightness = 71
+	CommandFourDigitValue      = 72
+	CommandFourDigitValueZeros = 73
+	CommandFourDigitDigit      = 74
+	CommandFourDigitSegment    = 75
+	CommandFourDigitScore      = 76
+	CommandFourDigitAllOn      = 78
+	CommandFourDigitAllOff     = 79
 )
 
 // GrovePiDriver is a driver for the GrovePi+ for I²C bus interface.
@@ -31,12 +139,17 @@ const (
 // https://forum.dexterindustries.com/t/pre-release-of-grovepis-firmware-v1-3-0-open-to-testers/5119
 //
 type GrovePiDriver struct {
-	name        string
-	digitalPins map[int]string
-	analogPins  map[int]string
-	mutex       *sync.Mutex
-	connector   i2c.Connector
-	connection  i2c.Connection
+	name           string
+	digitalPins    map[int]string
+	analogPins     map[int]string
+	mutex          *sync.Mutex
+	connector      i2c.Connector
+	connection     i2c.Connection
+	defaultPolicy  RetryPolicy
+	policies       map[byte]RetryPolicy
+	resetThreshold int
+	resetFunc      func() error
+	failures       int
 	i2c.Config
 }
 
@@ -47,15 +160,20 @@ type GrovePiDriver struct {
 // Optional params:
 //		i2c.WithBus(int):	bus to use with this driver
 //		i2c.WithAddress(int):	address to use with this driver
+//		WithRetryPolicy(byte, RetryPolicy):	retry policy of the command
+//		WithResetThreshold(int):	consecutive failures before the board is recovered
 //
 func NewGrovePiDriver(a i2c.Connector, options ...func(i2c.Config)) *GrovePiDriver {
 	d := &GrovePiDriver{
-		name:        gobot.DefaultName("GrovePi"),
-		digitalPins: make(map[int]string),
-		analogPins:  make(map[int]string),
-		mutex:       &sync.Mutex{},
-		connector:   a,
-		Config:      i2c.NewConfig(),
+		name:           gobot.DefaultName("GrovePi"),
+		digitalPins:    make(map[int]string),
+		analogPins:     make(map[int]string),
+		mutex:          &sync.Mutex{},
+		connector:      a,
+		defaultPolicy:  DefaultRetryPolicy,
+		policies:       make(map[byte]RetryPolicy),
+		resetThreshold: DefaultResetThreshold,
+		Config:         i2c.NewConfig(),
 	}
 
 	for _, option := range options {
@@ -78,7 +196,10 @@ func (d *GrovePiDriver) Connection() gobot.Connection { return d.connector.(gobo
 // Start initialized the GrovePi
 func (d *GrovePiDriver) Start() (err error) {
 	bus := d.GetBusOrDefault(d.connector.GetDefaultBus())
-	address := d.GetAddressOrDefault(grovePiAddress)
+	address := d.GetAddressOrDefault(DefaultAddress)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
 	d.connection, err = d.connector.GetConnection(address, bus)
 	if err != nil {
@@ -103,160 +224,509 @@ func (d *GrovePiDriver) Finalize() (err error) {
 
 // AnalogRead returns value from analog pin implementing the AnalogReader interface.
 func (d *GrovePiDriver) AnalogRead(pin string) (value int, err error) {
-	pin = getPin(pin)
-
-	var pinNum int
-	pinNum, err = strconv.Atoi(pin)
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return
 	}
 
-	value, err = d.readAnalog(byte(pinNum))
+	value, err = d.readAnalog(pinNum)
 
 	return
 }
 
 // DigitalRead performs a read on a digital pin.
 func (d *GrovePiDriver) DigitalRead(pin string) (val int, err error) {
-	pin = getPin(pin)
-
-	var pinNum int
-	pinNum, err = strconv.Atoi(pin)
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return
 	}
 
-	if dir, ok := d.digitalPins[pinNum]; !ok || dir != "input" {
-		err := d.PinMode(byte(pinNum), "input")
-		if err != nil {
-			return 0, err
-		}
-		d.digitalPins[pinNum] = "input"
-	}
-
-	val, err = d.readDigital(byte(pinNum))
+	val, err = d.readDigital(pinNum)
 
 	return
 }
 
 // UltrasonicRead performs a read on an ultrasonic pin.
 func (d *GrovePiDriver) UltrasonicRead(pin string) (val int, err error) {
-	pin = getPin(pin)
-
-	var pinNum int
-	pinNum, err = strconv.Atoi(pin)
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return
 	}
 
-	if dir, ok := d.digitalPins[pinNum]; !ok || dir != "input" {
-		err := d.PinMode(byte(pinNum), "input")
-		if err != nil {
-			return 0, err
-		}
-		d.digitalPins[pinNum] = "input"
-	}
-
-	val, err = d.readUltrasonic(byte(pinNum))
+	val, err = d.readUltrasonic(pinNum)
 
 	return
 }
 
 // DigitalWrite writes a value to a specific digital pin implementing the DigitalWriter interface.
 func (d *GrovePiDriver) DigitalWrite(pin string, val byte) (err error) {
-	pin = getPin(pin)
-
-	var pinNum int
-	pinNum, err = strconv.Atoi(pin)
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return
 	}
 
-	if dir, ok := d.digitalPins[pinNum]; !ok || dir != "output" {
-		err := d.PinMode(byte(pinNum), "output")
-		if err != nil {
-			return err
-		}
-		d.digitalPins[pinNum] = "output"
-	}
-
-	err = d.writeDigital(byte(pinNum), val)
+	err = d.writeDigital(pinNum, val)
 
 	return
 }
 
-// WriteAnalog writes PWM aka analog to the GrovePi. Not yet working.
-func (d *GrovePiDriver) WriteAnalog(pin byte, val byte) error {
-	buf := []byte{CommandWriteAnalog, pin, val, 0}
-	_, err := d.connection.Write(buf)
-
-	time.Sleep(2 * time.Millisecond)
+// pwmPins are the digital ports of the GrovePi with PWM output
+var pwmPins = map[byte]bool{3: true, 5: true, 6: true}
 
-	data := make([]byte, 1)
-	_, err = d.connection.Read(data)
+// WriteAnalog writes PWM aka analog level to the digital pin D3, D5 or D6, the firmware calls analogWrite with it.
+// The pin is switched to output first, on other pins analogWrite only switches the pin fully on or off
+func (d *GrovePiDriver) WriteAnalog(pin byte, val byte) error {
+	if !pwmPins[pin] {
+		return newCommandError(CommandWriteAnalog, pin, ErrorOutOfRange, fmt.Errorf("pin D%d has no PWM", pin))
+	}
+	_, err := d.executeOnPin(pin, "output", transfer{
+		cmd:   CommandWriteAnalog,
+		pin:   pin,
+		args:  [2]byte{val, 0},
+		delay: 2 * time.Millisecond,
+	})
+	return err
+}
 
+// ServoWrite sets the servo angle, 0 to 180 degrees, implementing the ServoWriter interface.
+func (d *GrovePiDriver) ServoWrite(pin string, angle byte) error {
+	if angle > 180 {
+		return outOfRange(CommandServoWrite, pin, fmt.Errorf("servo angle %d", angle))
+	}
+	pinNum, err := parsePin(pin)
+	if err != nil {
+		return err
+	}
+	_, err = d.executeOnPin(pinNum, "output", transfer{
+		cmd:   CommandServoWrite,
+		pin:   pinNum,
+		args:  [2]byte{angle, 0},
+		delay: 2 * time.Millisecond,
+	})
 	return err
 }
 
+// ServoDetach stops the servo pulses on the pin, the servo doesn't hold its position
+func (d *GrovePiDriver) ServoDetach(pin string) error {
+	return d.writeCommand(CommandServoDetach, pin, 0, 0)
+}
+
 // PinMode sets the pin mode to input or output.
 func (d *GrovePiDriver) PinMode(pin byte, mode string) error {
-	var b []byte
-	if mode == "output" {
-		b = []byte{CommandPinMode, pin, 1, 0}
-	} else {
-		b = []byte{CommandPinMode, pin, 0, 0}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.pinMode(pin, mode)
+}
+
+// SetRetryPolicy sets the retry policy of the command, zero command replaces the default policy
+func (d *GrovePiDriver) SetRetryPolicy(cmd byte, p RetryPolicy) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if cmd == 0 {
+		d.defaultPolicy = p
+		return
 	}
-	_, err := d.connection.Write(b)
+	d.policies[cmd] = p
+}
 
-	time.Sleep(2 * time.Millisecond)
+// RetryPolicy returns the retry policy used for the command
+func (d *GrovePiDriver) RetryPolicy(cmd byte) RetryPolicy {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	_, err = d.connection.ReadByte()
+	return d.retryPolicy(cmd)
+}
 
-	return err
+// ClearRetryPolicies drops the command retry policies and restores the default one
+func (d *GrovePiDriver) ClearRetryPolicies() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.defaultPolicy = DefaultRetryPolicy
+	d.policies = make(map[byte]RetryPolicy)
 }
 
-//readUltrasonic read ultrasonic ranger data
-func (d *GrovePiDriver) readUltrasonic(pin byte) (int, error) {
+// SetResetThreshold sets the number of consecutive failed commands after which
+// the board connection is recovered, zero or negative value disables recovery
+func (d *GrovePiDriver) SetResetThreshold(n int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.resetThreshold = n
+}
+
+// SetResetFunc sets the function called when the board is recovered after consecutive failures,
+// e.g. to pulse the board reset line, before the connection is reopened
+func (d *GrovePiDriver) SetResetFunc(f func() error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.resetFunc = f
+}
+
+// Reset recovers the board: calls the reset function if any, reopens the connection
+// and forgets cached pin modes so they are set again by the next command
+func (d *GrovePiDriver) Reset() error {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	err := d.PinMode(pin, "input")
+	return d.reset()
+}
+
+// FourDigitInit initializes the 4-digit display attached to the pin
+func (d *GrovePiDriver) FourDigitInit(pin string) error {
+	return d.writeCommand(CommandFourDigitInit, pin, 0, 0)
+}
+
+// FourDigitBrightness sets the 4-digit display brightness, 0 to 7
+func (d *GrovePiDriver) FourDigitBrightness(pin string, brightness byte) error {
+	if brightness > FourDigitMaxBrightness {
+		return outOfRange(CommandFourDigitBrightness, pin, fmt.Errorf("brightness %d", brightness))
+	}
+	return d.writeCommand(CommandFourDigitBrightness, pin, brightness, 0)
+}
+
+// FourDigitValue shows the decimal value on the 4-digit display, with or without leading zeros
+func (d *GrovePiDriver) FourDigitValue(pin string, value uint16, leadingZeros bool) error {
+	cmd := byte(CommandFourDigitValue)
+	if leadingZeros {
+		cmd = CommandFourDigitValueZeros
+	}
+	if value > 9999 {
+		return outOfRange(cmd, pin, fmt.Errorf("value %d", value))
+	}
+	return d.writeCommand(cmd, pin, byte(value&0xFF), byte(value>>8))
+}
+
+// FourDigitDigit shows the hexadecimal digit, 0 to 15, at the position, 0 to 3
+func (d *GrovePiDriver) FourDigitDigit(pin string, position, digit byte) error {
+	if position > 3 || digit > 15 {
+		return outOfRange(CommandFourDigitDigit, pin, fmt.Errorf("digit %d at %d", digit, position))
+	}
+	return d.writeCommand(CommandFourDigitDigit, pin, position, digit)
+}
+
+// FourDigitSegment lights the segments of the position, 0 to 3, bit 7 of the position 1 is the colon
+func (d *GrovePiDriver) FourDigitSegment(pin string, position, segments byte) error {
+	if position > 3 {
+		return outOfRange(CommandFourDigitSegment, pin, fmt.Errorf("position %d", position))
+	}
+	return d.writeCommand(CommandFourDigitSegment, pin, position, segments)
+}
+
+// FourDigitScore shows two values, 0 to 99, separated by the colon
+func (d *GrovePiDriver) FourDigitScore(pin string, left, right byte) error {
+	if left > 99 || right > 99 {
+		return outOfRange(CommandFourDigitScore, pin, fmt.Errorf("score %d:%d", left, right))
+	}
+	return d.writeCommand(CommandFourDigitScore, pin, left, right)
+}
+
+// FourDigitAll lights or clears all segments of the 4-digit display
+func (d *GrovePiDriver) FourDigitAll(pin string, on bool) error {
+	if on {
+		return d.writeCommand(CommandFourDigitAllOn, pin, 0, 0)
+	}
+	return d.writeCommand(CommandFourDigitAllOff, pin, 0, 0)
+}
+
+// LedBarInit initializes the LED bar attached to the pin, reversed bar counts from the green LED
+func (d *GrovePiDriver) LedBarInit(pin string, reversed bool) error {
+	return d.writeCommand(CommandLedBarInit, pin, boolToByte(reversed), 0)
+}
+
+// LedBarOrientation changes the LED bar orientation, reversed bar counts from the green LED
+func (d *GrovePiDriver) LedBarOrientation(pin string, reversed bool) error {
+	return d.writeCommand(CommandLedBarOrientation, pin, boolToByte(reversed), 0)
+}
+
+// LedBarLevel lights the LED bar up to the level, 0 to 10
+func (d *GrovePiDriver) LedBarLevel(pin string, level byte) error {
+	if level > LedBarLength {
+		return outOfRange(CommandLedBarLevel, pin, fmt.Errorf("level %d", level))
+	}
+	return d.writeCommand(CommandLedBarLevel, pin, level, 0)
+}
+
+// LedBarSetLed turns the LED, 1 to 10, on or off
+func (d *GrovePiDriver) LedBarSetLed(pin string, led byte, on bool) error {
+	if led < 1 || led > LedBarLength {
+		return outOfRange(CommandLedBarSetLed, pin, fmt.Errorf("led %d", led))
+	}
+	return d.writeCommand(CommandLedBarSetLed, pin, led, boolToByte(on))
+}
+
+// LedBarToggleLed toggles the LED, 1 to 10
+func (d *GrovePiDriver) LedBarToggleLed(pin string, led byte) error {
+	if led < 1 || led > LedBarLength {
+		return outOfRange(CommandLedBarToggleLed, pin, fmt.Errorf("led %d", led))
+	}
+	return d.writeCommand(CommandLedBarToggleLed, pin, led, 0)
+}
+
+// LedBarSetBits sets the state of all LEDs, bit 0 is the first LED
+func (d *GrovePiDriver) LedBarSetBits(pin string, bits uint16) error {
+	if bits >= 1<<LedBarLength {
+		return outOfRange(CommandLedBarSetBits, pin, fmt.Errorf("bits %b", bits))
+	}
+	return d.writeCommand(CommandLedBarSetBits, pin, byte(bits&0xFF), byte(bits>>8))
+}
+
+// LedBarBits returns the state of all LEDs, bit 0 is the first LED
+func (d *GrovePiDriver) LedBarBits(pin string) (uint16, error) {
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return 0, err
 	}
+	data, err := d.execute(transfer{
+		cmd:      CommandLedBarGetBits,
+		pin:      pinNum,
+		delay:    2 * time.Millisecond,
+		response: 3,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return uint16(data[1]) | uint16(data[2])<<8, nil
+}
 
-	buf := []byte{CommandReadUltrasonic, pin, 0, 0}
-	_, err = d.connection.Write(buf)
+// ChainableRGBInit initializes the chain of count RGB LEDs attached to the pin
+func (d *GrovePiDriver) ChainableRGBInit(pin string, count byte) error {
+	return d.writeCommand(CommandChainableRGBInit, pin, count, 0)
+}
 
-	time.Sleep(300 * time.Millisecond)
+// ChainableRGBTest lights the chain of count RGB LEDs with the test color, bit 0 is blue, bit 1 green and bit 2 red
+func (d *GrovePiDriver) ChainableRGBTest(pin string, count, color byte) error {
+	return d.writeCommand(CommandChainableRGBTest, pin, count, color)
+}
+
+// ChainableRGBPattern sets the color of the LEDs selected by the pattern relative to the LED, 0 is the first LED
+func (d *GrovePiDriver) ChainableRGBPattern(pin string, pattern, led, r, g, b byte) error {
+	if pattern > ChainableRGBPatternOutwards {
+		return outOfRange(CommandChainableRGBPattern, pin, fmt.Errorf("pattern %d", pattern))
+	}
+	return d.chainableRGBCommand(pin, [3]byte{r, g, b}, CommandChainableRGBPattern, pattern, led)
+}
+
+// ChainableRGBModulo sets the color of every divisor LED starting from the offset
+func (d *GrovePiDriver) ChainableRGBModulo(pin string, offset, divisor, r, g, b byte) error {
+	if divisor == 0 {
+		return outOfRange(CommandChainableRGBModulo, pin, fmt.Errorf("divisor %d", divisor))
+	}
+	return d.chainableRGBCommand(pin, [3]byte{r, g, b}, CommandChainableRGBModulo, offset, divisor)
+}
+
+// ChainableRGBLevel sets the color of the LEDs up to the level, reversed level counts from the end of the chain
+func (d *GrovePiDriver) ChainableRGBLevel(pin string, level byte, reversed bool, r, g, b byte) error {
+	return d.chainableRGBCommand(pin, [3]byte{r, g, b}, CommandChainableRGBLevel, level, boolToByte(reversed))
+}
 
-	_, err = d.connection.ReadByte()
+// chainableRGBCommand stores the color and runs the command using it in one critical section
+// so commands of different chains don't mix their colors
+func (d *GrovePiDriver) chainableRGBCommand(pin string, color [3]byte, cmd, arg1, arg2 byte) error {
+	pinNum, err := parsePin(pin)
+	if err != nil {
+		return err
+	}
 
-	time.Sleep(100 * time.Millisecond)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
 
-	raw := make([]byte, 3)
+	_, err = d.executeLocked(transfer{
+		cmd:   CommandChainableRGBStoreColor,
+		pin:   color[0],
+		args:  [2]byte{color[1], color[2]},
+		delay: 2 * time.Millisecond,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = d.executeLocked(transfer{cmd: cmd, pin: pinNum, args: [2]byte{arg1, arg2}, delay: 2 * time.Millisecond})
+	return err
+}
 
-	_, err = d.connection.Read(raw)
+// IRSetPin sets the pin of the IR receiver
+func (d *GrovePiDriver) IRSetPin(pin string) error {
+	return d.writeCommand(CommandIRSetPin, pin, 0, 0)
+}
 
+// IRRead returns the last code received by the IR receiver, ok is false when nothing was received.
+// Codes with other than 4 data bytes are reported as ErrorOutOfRange
+func (d *GrovePiDriver) IRRead() (code uint32, ok bool, err error) {
+	data, err := d.execute(transfer{
+		cmd:      CommandIRRead,
+		delay:    100 * time.Millisecond,
+		response: irResponseLen,
+	})
 	if err != nil {
-		return -1, err
+		return 0, false, err
+	}
+	if data[1] == irNoCode {
+		return 0, false, nil
+	}
+	if data[irDataLen] != necCodeLen {
+		return 0, false, newCommandError(CommandIRRead, 0, ErrorOutOfRange, fmt.Errorf("IR code of %d bytes", data[irDataLen]))
 	}
+	b := data[irData : irData+necCodeLen]
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), true, nil
+}
 
-	return int(raw[1])*255 + int(raw[2]), nil
+// EncoderEnable enables the encoder attached to the pin, the firmware counts its steps using the interrupt pin
+func (d *GrovePiDriver) EncoderEnable(pin string) error {
+	return d.writeCommand(CommandEncoderEnable, pin, 0, 0)
 }
 
+// EncoderDisable disables the encoder
+func (d *GrovePiDriver) EncoderDisable(pin string) error {
+	return d.writeCommand(CommandEncoderDisable, pin, 0, 0)
+}
+
+// EncoderRead returns the firmware step counter of the encoder, ok is false when the encoder isn't enabled
+func (d *GrovePiDriver) EncoderRead() (steps byte, ok bool, err error) {
+	data, err := d.execute(transfer{
+		cmd:      CommandEncoderRead,
+		delay:    2 * time.Millisecond,
+		response: 3,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if data[1] == counterNotReady {
+		return 0, false, nil
+	}
+	return data[2], true, nil
+}
+
+// ISRSet attaches the firmware interrupt handler to the digital pin, it counts the edges until they are read
+func (d *GrovePiDriver) ISRSet(pin string, edge byte) error {
+	pinNum, err := parsePin(pin)
+	if err != nil {
+		return err
+	}
+	_, err = d.executeOnPin(pinNum, "input", transfer{
+		cmd:   CommandISRSet,
+		pin:   pinNum,
+		args:  [2]byte{edge, 0},
+		delay: 2 * time.Millisecond,
+	})
+	return err
+}
+
+// ISRUnset detaches the firmware interrupt handler from the pin
+func (d *GrovePiDriver) ISRUnset(pin string) error {
+	return d.writeCommand(CommandISRUnset, pin, 0, 0)
+}
+
+// ISRRead returns the current level of the pin and the number of edges latched since the previous read
+func (d *GrovePiDriver) ISRRead(pin string) (level int, edges uint16, err error) {
+	pinNum, err := parsePin(pin)
+	if err != nil {
+		return 0, 0, err
+	}
+	data, err := d.execute(transfer{
+		cmd:      CommandISRRead,
+		pin:      pinNum,
+		delay:    2 * time.Millisecond,
+		response: 4,
+		validate: func(data []byte) error {
+			if data[1] > 1 {
+				return fmt.Errorf("digital value %d", data[1])
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(data[1]), uint16(data[2]) | uint16(data[3])<<8, nil
+}
+
+// DustSensorEnable enables the dust sensor attached to the interrupt pin D2
+func (d *GrovePiDriver) DustSensorEnable() error {
+	_, err := d.execute(transfer{cmd: CommandDustSensorEnable, delay: 2 * time.Millisecond})
+	return err
+}
+
+// DustSensorDisable disables the dust sensor
+func (d *GrovePiDriver) DustSensorDisable() error {
+	_, err := d.execute(transfer{cmd: CommandDustSensorDisable, delay: 2 * time.Millisecond})
+	return err
+}
+
+// DustSensorRead returns the low pulse occupancy in microseconds of the last firmware sampling window,
+// ok is false when there is no new sample
+func (d *GrovePiDriver) DustSensorRead() (occupancy uint32, ok bool, err error) {
+	data, err := d.execute(transfer{
+		cmd:      CommandDustSensorRead,
+		delay:    2 * time.Millisecond,
+		response: 5,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if data[1] != counterReady {
+		return 0, false, nil
+	}
+	return uint32(data[2]) | uint32(data[3])<<8 | uint32(data[4])<<16, true, nil
+}
+
+// FlowEnable enables the flow sensor attached to the interrupt pin D2
+func (d *GrovePiDriver) FlowEnable() error {
+	_, err := d.execute(transfer{cmd: CommandFlowEnable, delay: 2 * time.Millisecond})
+	return err
+}
+
+// FlowDisable disables the flow sensor
+func (d *GrovePiDriver) FlowDisable() error {
+	_, err := d.execute(transfer{cmd: CommandFlowDisable, delay: 2 * time.Millisecond})
+	return err
+}
+
+// FlowRead returns the number of pulses counted in the last firmware sampling window,
+// ok is false when there is no new sample
+func (d *GrovePiDriver) FlowRead() (pulses uint16, ok bool, err error) {
+	data, err := d.execute(transfer{
+		cmd:      CommandFlowRead,
+		delay:    2 * time.Millisecond,
+		response: 4,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if data[1] != counterReady {
+		return 0, false, nil
+	}
+	return uint16(data[2]) | uint16(data[3])<<8, true, nil
+}
+
+// ReadDHT reads temperature and humidity from the DHT11 sensor attached to the pin
 func (d *GrovePiDriver) ReadDHT(pin string) (float32, float32, error) {
+	return d.ReadDHTModule(pin, DHT11)
+}
 
-	pin = getPin(pin)
-	var pinNum int
-	pinNum, err := strconv.Atoi(pin)
+// ReadDHTModule reads temperature and humidity from the DHT sensor of the module type attached to the pin.
+// NaN and readings out of the sensor range are reported as ErrorOutOfRange
+func (d *GrovePiDriver) ReadDHTModule(pin string, module byte) (float32, float32, error) {
+	ranges, found := dhtRanges[module]
+	if !found {
+		return 0, 0, outOfRange(CommandReadDHT, pin, fmt.Errorf("DHT module type %d", module))
+	}
+	pinNum, err := parsePin(pin)
 	if err != nil {
 		return 0, 0, err
 	}
-	rawdata, err := d.readDHTRawData(byte(pinNum))
+	rawdata, err := d.readDHTRawData(pinNum, module, ranges)
 	if err != nil {
 		return 0, 0, err
 	}
+	t, h := decodeDHT(rawdata)
+	return t, h, nil
+}
+
+// decodeDHT returns temperature and humidity of the DHT response
+func decodeDHT(rawdata []byte) (float32, float32) {
 	temperatureData := rawdata[1:5]
 
 	tInt := int32(temperatureData[0]) | int32(temperatureData[1])<<8 | int32(temperatureData[2])<<16 | int32(temperatureData[3])<<24
@@ -266,39 +736,61 @@ func (d *GrovePiDriver) ReadDHT(pin string) (float32, float32, error) {
 	humInt := int32(humidityData[0]) | int32(humidityData[1])<<8 | int32(humidityData[2])<<16 | int32(humidityData[3])<<24
 	h := (*(*float32)(unsafe.Pointer(&humInt)))
 
-	return t, h, nil
+	return t, h
 }
 
-func (d *GrovePiDriver) readDHTRawData(pin byte) ([]byte, error) {
-
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-
-	err := d.PinMode(pin, "input")
+// readUltrasonic reads the distance in centimeters, the response with other echo is retried as ErrorBadEcho.
+// The firmware timeout marker means nothing is in range, it's reported as ErrorNoEcho without retrying
+// and it doesn't count as a bus failure
+func (d *GrovePiDriver) readUltrasonic(pin byte) (int, error) {
+	raw, err := d.executeOnPin(pin, "input", transfer{
+		cmd:      CommandReadUltrasonic,
+		pin:      pin,
+		delay:    300 * time.Millisecond,
+		response: 3,
+		timeout:  200 * time.Millisecond,
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	b := []byte{CommandReadDHT, pin, 0, 0}
-	_, err = d.connection.Write(b)
-	if err != nil {
-		return nil, err
+	distance := int(raw[1])<<8 | int(raw[2])
+	if distance == ultrasonicNoEcho {
+		return 0, newCommandError(CommandReadUltrasonic, pin, ErrorNoEcho, nil)
 	}
+	return distance, nil
+}
 
-	time.Sleep(600 * time.Millisecond)
-
-	_, err = d.connection.ReadByte()
+// readDHTRawData reads the DHT response, NaN and readings out of the sensor range are reported
+// as ErrorOutOfRange without retrying, they are sensor errors rather than bus failures
+func (d *GrovePiDriver) readDHTRawData(pin, module byte, ranges dhtRange) ([]byte, error) {
+	data, err := d.executeOnPin(pin, "input", transfer{
+		cmd:      CommandReadDHT,
+		pin:      pin,
+		args:     [2]byte{module},
+		delay:    600 * time.Millisecond,
+		response: 9,
+		timeout:  200 * time.Millisecond,
+	})
 	if err != nil {
 		return nil, err
 	}
-	time.Sleep(100 * time.Millisecond)
 
-	raw := make([]byte, 9)
-	_, err = d.connection.Read(raw)
-	if err != nil {
-		return nil, err
+	t, h := decodeDHT(data)
+	if math.IsNaN(float64(t)) || math.IsNaN(float64(h)) {
+		return nil, newCommandError(CommandReadDHT, pin, ErrorOutOfRange, errors.New("DHT reading is NaN"))
+	}
+	if !ranges.temperature.Contains(float64(t)) || !ranges.humidity.Contains(float64(h)) {
+		return nil, newCommandError(CommandReadDHT, pin, ErrorOutOfRange, fmt.Errorf("DHT temperature %v, humidity %v", t, h))
+	}
+	return data, nil
+}
+
+func boolToByte(b bool) byte {
+	if b {
+		return 1
 	}
-	return raw, nil
+	return 0
 }
 
 func getPin(pin string) string {
@@ -311,23 +803,42 @@ func getPin(pin string) string {
 	return pin
 }
 
-// readAnalog reads analog value from the GrovePi.
-func (d *GrovePiDriver) readAnalog(pin byte) (int, error) {
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
-
-	b := []byte{CommandReadAnalog, pin, 0, 0}
-	_, err := d.connection.Write(b)
+func parsePin(pin string) (byte, error) {
+	pinNum, err := strconv.Atoi(getPin(pin))
 	if err != nil {
-		return 0, err
+		return 0, newCommandError(0, 0, ErrorOutOfRange, fmt.Errorf("pin %s: %v", pin, err))
+	}
+	if pinNum < 0 || pinNum > 255 {
+		return 0, newCommandError(0, 0, ErrorOutOfRange, fmt.Errorf("pin %s", pin))
 	}
+	return byte(pinNum), nil
+}
 
-	time.Sleep(2 * time.Millisecond)
+// outOfRange reports the invalid argument of the command on the pin, invalid pin is reported instead
+func outOfRange(cmd byte, pin string, cause error) error {
+	pinNum, err := parsePin(pin)
+	if err != nil {
+		return err
+	}
+	return newCommandError(cmd, pinNum, ErrorOutOfRange, cause)
+}
 
-	data := make([]byte, 3)
-	_, err = d.connection.Read(data)
-	if err != nil || data[0] != CommandReadAnalog {
-		return -1, err
+// readAnalog reads analog value from the GrovePi.
+func (d *GrovePiDriver) readAnalog(pin byte) (int, error) {
+	data, err := d.execute(transfer{
+		cmd:      CommandReadAnalog,
+		pin:      pin,
+		delay:    2 * time.Millisecond,
+		response: 3,
+		validate: func(data []byte) error {
+			if v := int(data[1])*256 + int(data[2]); v > 1023 {
+				return fmt.Errorf("analog value %d", v)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	v1 := int(data[1])
@@ -337,37 +848,196 @@ func (d *GrovePiDriver) readAnalog(pin byte) (int, error) {
 
 // readDigital reads digitally from the GrovePi.
 func (d *GrovePiDriver) readDigital(pin byte) (val int, err error) {
-	d.mutex.Lock()
-	defer d.mutex.Unlock()
+	data, err := d.executeOnPin(pin, "input", transfer{
+		cmd:      CommandReadDigital,
+		pin:      pin,
+		delay:    2 * time.Millisecond,
+		response: 2,
+		validate: func(data []byte) error {
+			if data[1] > 1 {
+				return fmt.Errorf("digital value %d", data[1])
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(data[1]), nil
+}
 
-	buf := []byte{CommandReadDigital, pin, 0, 0}
-	_, err = d.connection.Write(buf)
+// writeDigital writes digitally to the GrovePi.
+func (d *GrovePiDriver) writeDigital(pin byte, val byte) error {
+	_, err := d.executeOnPin(pin, "output", transfer{
+		cmd:   CommandWriteDigital,
+		pin:   pin,
+		args:  [2]byte{val, 0},
+		delay: 2 * time.Millisecond,
+	})
+
+	return err
+}
+
+// writeCommand sends the command which doesn't return data
+func (d *GrovePiDriver) writeCommand(cmd byte, pin string, arg1, arg2 byte) error {
+	pinNum, err := parsePin(pin)
 	if err != nil {
-		return
+		return err
+	}
+	_, err = d.execute(transfer{cmd: cmd, pin: pinNum, args: [2]byte{arg1, arg2}, delay: 2 * time.Millisecond})
+	return err
+}
+
+// transfer describes single command exchange with the GrovePi
+type transfer struct {
+	cmd      byte
+	pin      byte
+	args     [2]byte
+	delay    time.Duration           // wait before the response is read
+	response int                     // response length including the echo byte, zero means single acknowledge byte
+	timeout  time.Duration           // minimal echo timeout, the retry policy one is used when greater
+	validate func(data []byte) error // checks the response payload, failure is reported as ErrorOutOfRange and not retried
+}
+
+// pinMode sets the pin mode, the caller must hold the mutex
+func (d *GrovePiDriver) pinMode(pin byte, mode string) error {
+	var m byte
+	if mode == "output" {
+		m = 1
 	}
+	_, err := d.executeLocked(transfer{cmd: CommandPinMode, pin: pin, args: [2]byte{m, 0}, delay: 2 * time.Millisecond})
+	if err != nil {
+		delete(d.digitalPins, int(pin))
+		return err
+	}
+	d.digitalPins[int(pin)] = mode
 
-	time.Sleep(2 * time.Millisecond)
+	return nil
+}
 
-	data := make([]byte, 2)
-	_, err = d.connection.Read(data)
-	if err != nil || data[0] != CommandReadDigital {
-		return 0, err
+// executeOnPin sets the pin mode if needed and executes the command in one critical section
+func (d *GrovePiDriver) executeOnPin(pin byte, mode string, t transfer) ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if m, ok := d.digitalPins[int(pin)]; !ok || m != mode {
+		if err := d.pinMode(pin, mode); err != nil {
+			return nil, err
+		}
 	}
 
-	return int(data[1]), err
+	return d.executeLocked(t)
 }
 
-// writeDigital writes digitally to the GrovePi.
-func (d *GrovePiDriver) writeDigital(pin byte, val byte) error {
+func (d *GrovePiDriver) execute(t transfer) ([]byte, error) {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	buf := []byte{CommandWriteDigital, pin, val, 0}
-	_, err := d.connection.Write(buf)
+	return d.executeLocked(t)
+}
 
-	time.Sleep(2 * time.Millisecond)
+// executeLocked runs the command according to its retry policy, the caller must hold the mutex
+func (d *GrovePiDriver) executeLocked(t transfer) (data []byte, err error) {
+	p := d.retryPolicy(t.cmd)
+	if t.timeout > p.Timeout {
+		p.Timeout = t.timeout
+	}
 
-	_, err = d.connection.ReadByte()
+	for attempt := 1; ; attempt++ {
+		data, err = d.attempt(t, p.Timeout)
+		if err == nil {
+			d.failures = 0
+			return data, nil
+		}
+		// the board answered, so invalid payload is neither retried nor counted as bus failure
+		if errors.Is(err, ErrorOutOfRange) {
+			return nil, err
+		}
+		if attempt >= p.attempts() {
+			break
+		}
+		time.Sleep(p.backoff(attempt))
+	}
+
+	d.failures++
+	if d.resetThreshold > 0 && d.failures >= d.resetThreshold {
+		d.failures = 0
+		if rerr := d.reset(); rerr != nil {
+			return nil, newCommandError(t.cmd, t.pin, ErrorBus, rerr)
+		}
+	}
+
+	return nil, err
+}
+
+func (d *GrovePiDriver) attempt(t transfer, timeout time.Duration) ([]byte, error) {
+	if d.connection == nil {
+		return nil, newCommandError(t.cmd, t.pin, ErrorBus, errNotConnected)
+	}
+
+	if _, err := d.connection.Write([]byte{t.cmd, t.pin, t.args[0], t.args[1]}); err != nil {
+		return nil, newCommandError(t.cmd, t.pin, ErrorBus, err)
+	}
+
+	time.Sleep(t.delay)
+
+	if t.response <= 0 {
+		if _, err := d.connection.ReadByte(); err != nil {
+			return nil, newCommandError(t.cmd, t.pin, ErrorBus, err)
+		}
+		return nil, nil
+	}
+
+	data := make([]byte, t.response)
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := d.connection.Read(data); err != nil {
+			return nil, newCommandError(t.cmd, t.pin, ErrorBus, err)
+		}
+		if data[0] == t.cmd {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			if data[0] == echoNotReady || data[0] == 0 {
+				return nil, newCommandError(t.cmd, t.pin, ErrorTimeout, nil)
+			}
+			return nil, newCommandError(t.cmd, t.pin, ErrorBadEcho, fmt.Errorf("echo %d", data[0]))
+		}
+		time.Sleep(echoPollInterval)
+	}
+
+	if t.validate != nil {
+		if err := t.validate(data); err != nil {
+			return nil, newCommandError(t.cmd, t.pin, ErrorOutOfRange, err)
+		}
+	}
+
+	return data, nil
+}
+
+func (d *GrovePiDriver) retryPolicy(cmd byte) RetryPolicy {
+	if p, found := d.policies[cmd]; found {
+		return p
+	}
+	return d.defaultPolicy
+}
+
+// reset recovers the board, the caller must hold the mutex
+func (d *GrovePiDriver) reset() (err error) {
+	if d.resetFunc != nil {
+		if err = d.resetFunc(); err != nil {
+			return err
+		}
+	}
+
+	d.digitalPins = make(map[int]string)
+	d.analogPins = make(map[int]string)
+
+	if d.connector == nil {
+		return errNotConnected
+	}
+	d.connection, err = d.connector.GetConnection(d.GetAddressOrDefault(DefaultAddress), d.GetBusOrDefault(d.connector.GetDefaultBus()))
 
 	return err
 }
@@ -0,0 +1,268 @@
+package gobot_driver
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// fakeConnection replays scripted responses and records written commands
+type fakeConnection struct {
+	written   [][]byte
+	responses [][]byte
+	writeErr  error
+}
+
+func (c *fakeConnection) Read(b []byte) (int, error) {
+	if len(c.responses) == 0 {
+		return 0, errors.New("no response")
+	}
+	n := copy(b, c.responses[0])
+	if len(c.responses) > 1 {
+		c.responses = c.responses[1:]
+	}
+	return n, nil
+}
+
+func (c *fakeConnection) Write(b []byte) (int, error) {
+	c.written = append(c.written, append([]byte{}, b...))
+	return len(b), c.writeErr
+}
+
+func (c *fakeConnection) Close() error                       { return nil }
+func (c *fakeConnection) ReadByte() (byte, error)            { return 0, nil }
+func (c *fakeConnection) ReadByteData(uint8) (uint8, error)  { return 0, nil }
+func (c *fakeConnection) ReadWordData(uint8) (uint16, error) { return 0, nil }
+func (c *fakeConnection) WriteByte(byte) error               { return nil }
+func (c *fakeConnection) WriteByteData(uint8, uint8) error   { return nil }
+func (c *fakeConnection) WriteWordData(uint8, uint16) error  { return nil }
+func (c *fakeConnection) WriteBlockData(uint8, []byte) error { return nil }
+
+type fakeConnector struct {
+	connection  *fakeConnection
+	connections int
+}
+
+func (c *fakeConnector) GetConnection(int, int) (i2c.Connection, error) {
+	c.connections++
+	return c.connection, nil
+}
+
+func (c *fakeConnector) GetDefaultBus() int { return 1 }
+
+func newTestGrovePiDriver(responses ...[]byte) (*GrovePiDriver, *fakeConnector) {
+	connector := &fakeConnector{connection: &fakeConnection{responses: responses}}
+	d := NewGrovePiDriver(connector, WithRetryPolicy(0, RetryPolicy{Attempts: 3}))
+	if err := d.Start(); err != nil {
+		panic(err)
+	}
+	return d, connector
+}
+
+func TestGrovePiDriverAnalogRead(t *testing.T) {
+	d, _ := newTestGrovePiDriver([]byte{CommandReadAnalog, 2, 1})
+
+	val, err := d.AnalogRead("A0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 513 {
+		t.Errorf("expected 513, got %d", val)
+	}
+}
+
+func TestGrovePiDriverIRRead(t *testing.T) {
+	// echo, IRSendRev header with length, pulse times and 4 data bytes, NEC code
+	d, _ := newTestGrovePiDriver(
+		[]byte{CommandIRRead, 10, 180, 90, 11, 33, 4, 0x00, 0xFF, 0x45, 0xBA},
+		[]byte{CommandIRRead, irNoCode},
+		[]byte{CommandIRRead, 8, 180, 90, 11, 33, 2, 0x45, 0xBA})
+
+	code, ok, err := d.IRRead()
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	if code != 0x00FF45BA {
+		t.Errorf("expected 00FF45BA, got %08X", code)
+	}
+	if _, ok, err := d.IRRead(); ok || err != nil {
+		t.Errorf("expected empty buffer, got %v %v", ok, err)
+	}
+	if _, _, err := d.IRRead(); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected short code error, got %v", err)
+	}
+}
+
+func TestGrovePiDriverRetriesBadEcho(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandReadDigital, 0, 0}, []byte{CommandReadAnalog, 0, 7})
+
+	val, err := d.AnalogRead("A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != 7 {
+		t.Errorf("expected 7, got %d", val)
+	}
+	if n := len(connector.connection.written); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestGrovePiDriverErrorClasses(t *testing.T) {
+	d, _ := newTestGrovePiDriver([]byte{CommandReadDigital, 0, 0})
+	if _, err := d.AnalogRead("A0"); !errors.Is(err, ErrorBadEcho) {
+		t.Errorf("expected bad echo error, got %v", err)
+	}
+
+	d, _ = newTestGrovePiDriver([]byte{echoNotReady, 0, 0})
+	if _, err := d.AnalogRead("A0"); !errors.Is(err, ErrorTimeout) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+
+	d, _ = newTestGrovePiDriver([]byte{CommandReadAnalog, 4, 0})
+	if _, err := d.AnalogRead("A0"); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected out of range error, got %v", err)
+	}
+
+	d, connector := newTestGrovePiDriver()
+	connector.connection.writeErr = errors.New("i/o error")
+	err := d.DigitalWrite("D3", 1)
+	if !errors.Is(err, ErrorBus) {
+		t.Errorf("expected bus error, got %v", err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Pin != 3 {
+		t.Errorf("expected command error on pin 3, got %v", err)
+	}
+}
+
+func TestGrovePiDriverArgumentErrors(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandIRRead, 8, 180, 90, 11, 33, 2, 0x45, 0xBA})
+
+	for name, call := range map[string]func() error{
+		"ServoWrite":     func() error { return d.ServoWrite("D5", 181) },
+		"LedBarLevel":    func() error { return d.LedBarLevel("D5", 11) },
+		"ReadDHT":        func() error { _, _, err := d.ReadDHTModule("D5", 9); return err },
+		"FourDigitDigit": func() error { return d.FourDigitDigit("D5", 4, 0) },
+	} {
+		var cmdErr *CommandError
+		err := call()
+		if !errors.As(err, &cmdErr) || !errors.Is(err, ErrorOutOfRange) || cmdErr.Pin != 5 {
+			t.Errorf("%s: expected out of range command error on pin 5, got %v", name, err)
+		}
+	}
+	if len(connector.connection.written) != 0 {
+		t.Errorf("invalid arguments shouldn't be written, got %v", connector.connection.written)
+	}
+
+	var cmdErr *CommandError
+	if _, _, err := d.IRRead(); !errors.As(err, &cmdErr) || cmdErr.Command != CommandIRRead {
+		t.Errorf("expected IR read command error, got %v", err)
+	}
+	if err := d.DigitalWrite("Dx", 1); !errors.As(err, &cmdErr) {
+		t.Errorf("expected invalid pin command error, got %v", err)
+	}
+}
+
+func TestGrovePiDriverInvalidPayloadNotRetried(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandReadAnalog, 4, 0})
+	resets := 0
+	d.SetResetFunc(func() error {
+		resets++
+		return nil
+	})
+
+	for i := 0; i < DefaultResetThreshold; i++ {
+		if _, err := d.AnalogRead("A0"); !errors.Is(err, ErrorOutOfRange) {
+			t.Fatalf("expected out of range error, got %v", err)
+		}
+	}
+	if n := len(connector.connection.written); n != DefaultResetThreshold {
+		t.Errorf("invalid payload shouldn't be retried, got %d writes", n)
+	}
+	if resets != 0 {
+		t.Errorf("invalid payload shouldn't reset the board, got %d resets", resets)
+	}
+}
+
+func TestGrovePiDriverResetAfterConsecutiveFailures(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandReadDigital, 0, 0})
+	resets := 0
+	d.SetResetFunc(func() error {
+		resets++
+		return nil
+	})
+
+	for i := 0; i < DefaultResetThreshold; i++ {
+		_, _ = d.AnalogRead("A0")
+	}
+
+	if resets != 1 {
+		t.Errorf("expected single reset, got %d", resets)
+	}
+	if connector.connections != 2 {
+		t.Errorf("expected connection to be reopened, got %d connections", connector.connections)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{Backoff: 10, MaxBackoff: 35}
+	expected := []int{10, 20, 35, 35}
+	for i, e := range expected {
+		if b := p.backoff(i + 1); int(b) != e {
+			t.Errorf("attempt %d: expected %d, got %d", i+1, e, b)
+		}
+	}
+}
+
+func dhtResponse(t, h float32) []byte {
+	data := []byte{CommandReadDHT}
+	for _, v := range []float32{t, h} {
+		bits := math.Float32bits(v)
+		data = append(data, byte(bits), byte(bits>>8), byte(bits>>16), byte(bits>>24))
+	}
+	return data
+}
+
+func TestGrovePiDriverReadDHTModule(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandPinMode}, dhtResponse(-12.5, 99))
+
+	temp, humid, err := d.ReadDHTModule("D7", DHT22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temp != -12.5 || humid != 99 {
+		t.Errorf("unexpected reading %v, %v", temp, humid)
+	}
+	if cmd := connector.connection.written[1]; cmd[0] != CommandReadDHT || cmd[2] != DHT22 {
+		t.Errorf("expected DHT22 module type in command %v", cmd)
+	}
+
+	d, _ = newTestGrovePiDriver([]byte{CommandPinMode}, dhtResponse(25, 96))
+	if _, humid, err := d.ReadDHTModule("D7", DHT11); err != nil || humid != 96 {
+		t.Errorf("expected DHT11 humidity 96, got %v %v", humid, err)
+	}
+}
+
+func TestGrovePiDriverReadDHTOutOfRange(t *testing.T) {
+	d, connector := newTestGrovePiDriver([]byte{CommandPinMode}, dhtResponse(float32(math.NaN()), 40), dhtResponse(-12.5, 99))
+	resets := 0
+	d.SetResetFunc(func() error {
+		resets++
+		return nil
+	})
+
+	for i := 0; i < DefaultResetThreshold; i++ {
+		if _, _, err := d.ReadDHTModule("D7", DHT11); !errors.Is(err, ErrorOutOfRange) {
+			t.Fatalf("expected out of range error, got %v", err)
+		}
+	}
+	if resets != 0 {
+		t.Errorf("sensor errors shouldn't reset the board, got %d resets", resets)
+	}
+	if n := len(connector.connection.written); n != DefaultResetThreshold+1 {
+		t.Errorf("sensor errors shouldn't be retried, expected %d commands, got %d", DefaultResetThreshold+1, n)
+	}
+}
@@ -0,0 +1,124 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// Error classes reported by GrovePi commands. Every error returned by a GrovePi command is
+// a *CommandError which unwraps to one of them, so they can be checked with errors.Is
+var (
+	ErrorBus        = errors.New("i2c bus error")
+	ErrorBadEcho    = errors.New("unexpected command echo")
+	ErrorTimeout    = errors.New("command timed out")
+	ErrorOutOfRange = errors.New("value out of range")
+	ErrorNoEcho     = errors.New("no ultrasonic echo")
+)
+
+// CommandError describes failed GrovePi command
+type CommandError struct {
+	Command byte
+	Pin     byte
+	Kind    error
+	Cause   error
+}
+
+func newCommandError(cmd, pin byte, kind, cause error) *CommandError {
+	return &CommandError{Command: cmd, Pin: pin, Kind: kind, Cause: cause}
+}
+
+// Error implements error interface
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("grovepi command %d on pin %d: %v", e.Command, e.Pin, e.Kind)
+	if e.Cause != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Cause)
+	}
+	return msg
+}
+
+// Unwrap returns error class of the failure
+func (e *CommandError) Unwrap() error { return e.Kind }
+
+// RetryPolicy describes how a GrovePi command is retried
+//
+//	Attempts	- total number of attempts, at least one is always made
+//	Backoff		- delay before the second attempt, doubled for every next one
+//	MaxBackoff	- upper limit of the delay between attempts, zero means no limit
+//	Timeout		- how long to wait for the command echo before the attempt is considered timed out
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Timeout    time.Duration
+}
+
+const (
+	// DefaultResetThreshold is the number of consecutive failed commands after which the board connection is recovered
+	DefaultResetThreshold = 5
+
+	echoPollInterval = 10 * time.Millisecond
+)
+
+// DefaultRetryPolicy is used for commands without their own policy
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    5 * time.Millisecond,
+	MaxBackoff: 100 * time.Millisecond,
+	Timeout:    50 * time.Millisecond,
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.Attempts < 1 {
+		return 1
+	}
+	return p.Attempts
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	b := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || b < p.MaxBackoff); i++ {
+		b *= 2
+	}
+	if p.MaxBackoff > 0 && b > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return b
+}
+
+// WithRetryPolicy option sets the retry policy of the command, use zero command to replace the default policy
+func WithRetryPolicy(cmd byte, p RetryPolicy) func(i2c.Config) {
+	return func(c i2c.Config) {
+		if d, ok := c.(*GrovePiDriver); ok {
+			d.SetRetryPolicy(cmd, p)
+		}
+	}
+}
+
+// WithResetThreshold option sets the number of consecutive failed commands after which
+// the board connection is recovered, zero or negative value disables recovery
+func WithResetThreshold(n int) func(i2c.Config) {
+	return func(c i2c.Config) {
+		if d, ok := c.(*GrovePiDriver); ok {
+			d.SetResetThreshold(n)
+		}
+	}
+}
+
+// CommandByName returns the GrovePi command by its configuration name
+func CommandByName(name string) (byte, bool) {
+	cmd, found := commandNames[name]
+	return cmd, found
+}
+
+var commandNames = map[string]byte{
+	"digitalRead":    CommandReadDigital,
+	"digitalWrite":   CommandWriteDigital,
+	"analogRead":     CommandReadAnalog,
+	"analogWrite":    CommandWriteAnalog,
+	"pinMode":        CommandPinMode,
+	"ultrasonicRead": CommandReadUltrasonic,
+	"dhtRead":        CommandReadDHT,
+}
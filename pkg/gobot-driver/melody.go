@@ -0,0 +1,165 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorInvalidMelody is returned for melodies which can't be parsed
+var ErrorInvalidMelody = errors.New("invalid melody")
+
+// RTTTL defaults used when the ringtone doesn't set them and for plain note sequences
+const (
+	defaultNoteDuration = 4
+	defaultNoteOctave   = 6
+	defaultMelodyBPM    = 63
+)
+
+// maxMelodyBPM limits the tempo, the shortest notes of faster melodies can't be told apart
+const maxMelodyBPM = 900
+
+// noteSemitones are the semitones of the notes above C, h is the German b
+var noteSemitones = map[byte]int{'c': 0, 'd': 2, 'e': 4, 'f': 5, 'g': 7, 'a': 9, 'b': 11, 'h': 11}
+
+// Note is a tone of the melody, zero frequency is a pause
+type Note struct {
+	Frequency float64       `json:"frequency"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Melody is a named sequence of notes
+type Melody struct {
+	Name  string `json:"name"`
+	Notes []Note `json:"notes"`
+}
+
+// Duration returns the playing time of the melody
+func (m *Melody) Duration() time.Duration {
+	var d time.Duration
+	for _, n := range m.Notes {
+		d += n.Duration
+	}
+	return d
+}
+
+// ParseMelody parses the RTTTL ringtone, e.g. "Beep:d=8,o=6,b=120:c,p,c,4g.5",
+// or the plain note sequence "8c6,8p,4g.5" played with the RTTTL defaults d=4, o=6 and b=63
+func ParseMelody(s string) (*Melody, error) {
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 1:
+		return parseNotes("", parts[0], defaultNoteDuration, defaultNoteOctave, defaultMelodyBPM)
+	case 3:
+	default:
+		return nil, fmt.Errorf("%w: %q should be name:defaults:notes or notes", ErrorInvalidMelody, s)
+	}
+
+	duration, octave, bpm := defaultNoteDuration, defaultNoteOctave, defaultMelodyBPM
+	for _, def := range strings.Split(parts[1], ",") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		kv := strings.SplitN(def, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%w: default %q", ErrorInvalidMelody, def)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: default %q", ErrorInvalidMelody, def)
+		}
+		valid := false
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "d":
+			duration, valid = v, v > 0
+		case "o":
+			octave, valid = v, v >= 0 && v <= 9
+		case "b":
+			bpm, valid = v, v > 0 && v <= maxMelodyBPM
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: default %q", ErrorInvalidMelody, def)
+		}
+	}
+	return parseNotes(strings.TrimSpace(parts[0]), parts[2], duration, octave, bpm)
+}
+
+// parseNotes parses the comma separated RTTTL notes, a whole note lasts four beats
+func parseNotes(name, notes string, duration, octave, bpm int) (*Melody, error) {
+	m := &Melody{Name: name}
+	whole := 4 * time.Minute / time.Duration(bpm)
+
+	for _, token := range strings.Split(notes, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token == "" {
+			continue
+		}
+		n, err := parseNote(token, duration, octave, whole)
+		if err != nil {
+			return nil, err
+		}
+		m.Notes = append(m.Notes, n)
+	}
+	if len(m.Notes) == 0 {
+		return nil, fmt.Errorf("%w: %q has no notes", ErrorInvalidMelody, name)
+	}
+	return m, nil
+}
+
+// parseNote parses [duration]note[#][.][octave][.], the dot makes the note half as long again
+func parseNote(token string, duration, octave int, whole time.Duration) (Note, error) {
+	invalid := fmt.Errorf("%w: note %q", ErrorInvalidMelody, token)
+	s := token
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i > 0 {
+		duration, _ = strconv.Atoi(s[:i])
+		s = s[i:]
+	}
+	if duration <= 0 || s == "" {
+		return Note{}, invalid
+	}
+
+	letter, s := s[0], s[1:]
+	semitone, isNote := noteSemitones[letter]
+	if !isNote && letter != 'p' {
+		return Note{}, invalid
+	}
+	if strings.HasPrefix(s, "#") {
+		semitone++
+		s = s[1:]
+	}
+	dotted := false
+	if strings.HasPrefix(s, ".") {
+		dotted = true
+		s = s[1:]
+	}
+	if strings.HasSuffix(s, ".") {
+		dotted = true
+		s = s[:len(s)-1]
+	}
+	if s != "" {
+		o, err := strconv.Atoi(s)
+		if err != nil || o < 0 || o > 9 {
+			return Note{}, invalid
+		}
+		octave = o
+	}
+
+	n := Note{Duration: whole / time.Duration(duration)}
+	if dotted {
+		n.Duration += n.Duration / 2
+	}
+	if isNote {
+		// A4 is 440 Hz, the octave starts at C
+		n.Frequency = 440 * math.Pow(2, float64(semitone-9)/12+float64(octave-4))
+	}
+	return n, nil
+}
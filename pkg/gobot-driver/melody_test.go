@@ -0,0 +1,50 @@
+package gobot_driver
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseMelody(t *testing.T) {
+	m, err := ParseMelody("Beep:d=8,o=5,b=120:a,p,4c#6.,16h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "Beep" || len(m.Notes) != 4 {
+		t.Fatalf("unexpected melody %+v", m)
+	}
+	// a whole note lasts 4 beats of 500ms
+	want := []Note{
+		{880, 250 * time.Millisecond},
+		{0, 250 * time.Millisecond},
+		{1108.73, 750 * time.Millisecond},
+		{987.77, 125 * time.Millisecond},
+	}
+	for i, n := range m.Notes {
+		if math.Abs(n.Frequency-want[i].Frequency) > 0.01 || n.Duration != want[i].Duration {
+			t.Errorf("note %d: got %+v, want %+v", i, n, want[i])
+		}
+	}
+
+	m, err = ParseMelody("8e6, 8p, 4g.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	whole := 4 * time.Minute / 63
+	if m.Duration() != whole/8+whole/8+whole/4+whole/4/2 {
+		t.Errorf("unexpected duration %v of the sequence with defaults", m.Duration())
+	}
+
+	if _, err := ParseMelody("Low:o=0,b=900:c,p"); err != nil {
+		t.Errorf("octave 0 and 900 bpm should be valid, got %v", err)
+	}
+
+	for _, s := range []string{"", "name:notes", "Bad:d=4,o=5,b=100:8x", "Bad:z=1:c", "Bad:d=4::",
+		"Bad:d=0:c", "Bad:o=-1:c", "Bad:o=10:c", "Bad:b=0:c", "Bad:b=901:c"} {
+		if _, err := ParseMelody(s); !errors.Is(err, ErrorInvalidMelody) {
+			t.Errorf("%q: expected invalid melody, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorInvalidParam is returned by commands called with missing or mistyped params
+var ErrorInvalidParam = errors.New("invalid command param")
+
+// floatParam returns numeric command param, API params are decoded from JSON so numbers come as float64
+func floatParam(params map[string]interface{}, name string) (float64, error) {
+	switch v := params[name].(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case nil:
+		return 0, fmt.Errorf("%w: %s is missing", ErrorInvalidParam, name)
+	default:
+		return 0, fmt.Errorf("%w: %s is %T, not a number", ErrorInvalidParam, name, v)
+	}
+}
+
+// floatParamOr returns numeric command param or the default when the param is missing
+func floatParamOr(params map[string]interface{}, name string, def float64) (float64, error) {
+	if _, found := params[name]; !found {
+		return def, nil
+	}
+	return floatParam(params, name)
+}
+
+// intParam returns integer command param from min to max, the range is checked before the float is converted
+func intParam(params map[string]interface{}, name string, min, max int) (int, error) {
+	v, err := floatParam(params, name)
+	if err != nil {
+		return 0, err
+	}
+	if !(v >= float64(min) && v <= float64(max)) {
+		return 0, fmt.Errorf("%w: %s is %v, should be from %d to %d", ErrorInvalidParam, name, v, min, max)
+	}
+	return int(v), nil
+}
+
+// intParamOr returns integer command param from min to max or the default when the param is missing
+func intParamOr(params map[string]interface{}, name string, def, min, max int) (int, error) {
+	if _, found := params[name]; !found {
+		return def, nil
+	}
+	return intParam(params, name, min, max)
+}
+
+// boolParamOr returns boolean command param or the default when the param is missing
+func boolParamOr(params map[string]interface{}, name string, def bool) (bool, error) {
+	switch v := params[name].(type) {
+	case bool:
+		return v, nil
+	case nil:
+		return def, nil
+	default:
+		return false, fmt.Errorf("%w: %s is %T, not a boolean", ErrorInvalidParam, name, v)
+	}
+}
+
+// stringParam returns string command param
+func stringParam(params map[string]interface{}, name string) (string, error) {
+	switch v := params[name].(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", fmt.Errorf("%w: %s is missing", ErrorInvalidParam, name)
+	default:
+		return "", fmt.Errorf("%w: %s is %T, not a string", ErrorInvalidParam, name, v)
+	}
+}
@@ -0,0 +1,108 @@
+package gobot_driver
+
+import (
+	"sync"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// Value is the event of the processed sensor value published next to the raw aio.Data, its data is Measurement
+const Value = "value"
+
+// Measurement is the processed sensor value
+type Measurement struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+	Raw   float64 `json:"raw"`
+}
+
+// Pipeline processes aio.Data events of the sensor and publishes Value events on the sensor
+type Pipeline struct {
+	mutex       sync.Mutex
+	calibration *Calibration
+	last        *Measurement
+	unsubscribe func()
+}
+
+// NewPipeline creates new pipeline, nil calibration keeps raw values
+func NewPipeline(c *Calibration) *Pipeline {
+	return &Pipeline{calibration: c}
+}
+
+// Attach starts processing the source events, the pipeline can be attached to a single source
+func (p *Pipeline) Attach(source gobot.Eventer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.unsubscribe != nil {
+		return
+	}
+	source.AddEvent(Value)
+	p.unsubscribe = subscribe(source, func(evt *gobot.Event) {
+		if evt.Name != aio.Data {
+			return
+		}
+		if raw, ok := numeric(evt.Data); ok {
+			if m, ok := p.process(raw); ok {
+				source.Publish(Value, m)
+			}
+		}
+	})
+}
+
+// Detach stops processing the source events
+func (p *Pipeline) Detach() {
+	p.mutex.Lock()
+	unsubscribe := p.unsubscribe
+	p.unsubscribe = nil
+	p.mutex.Unlock()
+
+	// the events are processed under the mutex
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+}
+
+// Last returns the last processed value, ok is false before the first one
+func (p *Pipeline) Last() (m Measurement, ok bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.last == nil {
+		return Measurement{}, false
+	}
+	return *p.last, true
+}
+
+// process converts the raw value, ok is false when the value shouldn't be published
+func (p *Pipeline) process(raw float64) (m Measurement, ok bool) {
+	m = Measurement{Value: raw, Raw: raw}
+	if p.calibration != nil {
+		m.Value = p.calibration.Convert(raw)
+		m.Unit = p.calibration.Unit
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.last = &m
+	return m, true
+}
+
+// numeric returns the event data as float64
+func numeric(data interface{}) (float64, bool) {
+	switch v := data.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case Measurement:
+		return v.Value, true
+	}
+	return 0, false
+}
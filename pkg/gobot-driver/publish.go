@@ -0,0 +1,81 @@
+package gobot_driver
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Publish policy modes
+const (
+	// PublishOnChange publishes the samples which differ from the last published one by more than epsilon
+	PublishOnChange = "onChange"
+	// PublishAlways publishes every sample
+	PublishAlways = "always"
+	// PublishHeartbeat publishes a sample once per heartbeat interval
+	PublishHeartbeat = "heartbeat"
+	// PublishOnChangeOrHeartbeat publishes the changed samples and a sample per heartbeat interval without a change
+	PublishOnChangeOrHeartbeat = "onChangeOrHeartbeat"
+)
+
+// PublishPolicy decides which samples of the polling sensor are published, the first sample is always published
+type PublishPolicy struct {
+	Mode      string        `json:"mode"`
+	Epsilon   float64       `json:"epsilon,omitempty"`
+	Heartbeat time.Duration `json:"heartbeat,omitempty"`
+}
+
+// DefaultPublishPolicy publishes every change
+var DefaultPublishPolicy = PublishPolicy{Mode: PublishOnChange}
+
+// Validate checks the policy mode and its params
+func (p PublishPolicy) Validate() error {
+	switch p.Mode {
+	case PublishOnChange, PublishAlways:
+	case PublishHeartbeat, PublishOnChangeOrHeartbeat:
+		if p.Heartbeat <= 0 {
+			return fmt.Errorf("%w: %s heartbeat %v", ErrorOutOfRange, p.Mode, p.Heartbeat)
+		}
+	default:
+		return fmt.Errorf("%w: publish mode %q", ErrorOutOfRange, p.Mode)
+	}
+	if p.Epsilon < 0 {
+		return fmt.Errorf("%w: publish epsilon %v", ErrorOutOfRange, p.Epsilon)
+	}
+	return nil
+}
+
+// publisher applies the publish policy to the samples of a single value
+type publisher struct {
+	policy    PublishPolicy
+	last      float64
+	at        time.Time
+	published bool
+}
+
+// publish returns true when the sample should be published and remembers it
+func (p *publisher) publish(value float64, now time.Time) bool {
+	if p.published && !p.due(value, now) {
+		return false
+	}
+	p.last, p.at, p.published = value, now, true
+	return true
+}
+
+func (p *publisher) due(value float64, now time.Time) bool {
+	changed := math.Abs(value-p.last) > p.policy.Epsilon || (p.policy.Epsilon == 0 && value != p.last)
+	beat := now.Sub(p.at) >= p.policy.Heartbeat
+	switch p.policy.Mode {
+	case PublishAlways:
+		return true
+	case PublishHeartbeat:
+		return beat
+	case PublishOnChangeOrHeartbeat:
+		return changed || beat
+	default:
+		return changed
+	}
+}
+
+// reset forgets the last published sample
+func (p *publisher) reset() { p.published = false }
@@ -0,0 +1,33 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPublisher(t *testing.T) {
+	start := time.Now()
+	samples := []float64{0, 0, 0.3, 0.6, 0.6, 0.6}
+	for _, c := range []struct {
+		policy PublishPolicy
+		want   []bool
+	}{
+		{DefaultPublishPolicy, []bool{true, false, true, true, false, false}},
+		{PublishPolicy{Mode: PublishOnChange, Epsilon: 0.5}, []bool{true, false, false, true, false, false}},
+		{PublishPolicy{Mode: PublishAlways}, []bool{true, true, true, true, true, true}},
+		{PublishPolicy{Mode: PublishHeartbeat, Heartbeat: 2 * time.Second}, []bool{true, false, true, false, true, false}},
+		{PublishPolicy{Mode: PublishOnChangeOrHeartbeat, Epsilon: 0.25, Heartbeat: 2 * time.Second}, []bool{true, false, true, true, false, true}},
+	} {
+		p := publisher{policy: c.policy}
+		for i, v := range samples {
+			if got := p.publish(v, start.Add(time.Duration(i)*time.Second)); got != c.want[i] {
+				t.Errorf("%+v: sample %d (%v) published %v", c.policy, i, v, got)
+			}
+		}
+	}
+
+	if err := (PublishPolicy{Mode: PublishHeartbeat}).Validate(); !errors.Is(err, ErrorOutOfRange) {
+		t.Errorf("expected missing heartbeat error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package gobot_driver
+
+import (
+	"math"
+	"sort"
+)
+
+// Range is the valid range of values in engineering units
+type Range struct {
+	Min, Max float64
+}
+
+// Contains returns true when the value is within the range
+func (r *Range) Contains(value float64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+// ProfileLevel is the named level of the sensor value starting at From
+type ProfileLevel struct {
+	Name string  `json:"name"`
+	From float64 `json:"from"`
+}
+
+// SensorProfile describes the conversion of raw ADC counts of an analog sensor
+type SensorProfile struct {
+	Calibration *Calibration
+	Range       *Range
+	Levels      []ProfileLevel
+}
+
+// NewSensorProfile creates new profile, nil calibration keeps raw values, nil range accepts any value
+func NewSensorProfile(c *Calibration, r *Range, levels ...ProfileLevel) *SensorProfile {
+	if c == nil {
+		c = NewLinearCalibration(1, 0, "")
+	}
+	sorted := append([]ProfileLevel{}, levels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+	return &SensorProfile{Calibration: c, Range: r, Levels: sorted}
+}
+
+// Valid returns true when the converted value is a number within the valid range
+func (p *SensorProfile) Valid(value float64) bool {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return false
+	}
+	return p.Range == nil || p.Range.Contains(value)
+}
+
+// Level returns the name of the highest level the value reached, empty below the lowest level
+func (p *SensorProfile) Level(value float64) string {
+	level := ""
+	for _, l := range p.Levels {
+		if value < l.From {
+			break
+		}
+		level = l.Name
+	}
+	return level
+}
+
+// sensorProfiles are the profiles of common Grove analog modules on 5V GrovePi
+var sensorProfiles = map[string]func() *SensorProfile{
+	"moisture": func() *SensorProfile {
+		return NewSensorProfile(nil, &Range{0, 950},
+			ProfileLevel{"dry", 0}, ProfileLevel{"humid", 300}, ProfileLevel{"water", 700})
+	},
+	"air-quality": func() *SensorProfile {
+		return NewSensorProfile(nil, nil,
+			ProfileLevel{"fresh", 0}, ProfileLevel{"low-pollution", 300}, ProfileLevel{"high-pollution", 700})
+	},
+	"loudness": func() *SensorProfile {
+		return NewSensorProfile(nil, nil,
+			ProfileLevel{"quiet", 0}, ProfileLevel{"moderate", 200}, ProfileLevel{"loud", 500})
+	},
+	"uv": func() *SensorProfile {
+		// UV index is the sensor voltage divided by 0.1V
+		return NewSensorProfile(NewLinearCalibration(5.0/1023/0.1, 0, "UV index"), &Range{0, 15},
+			ProfileLevel{"low", 0}, ProfileLevel{"moderate", 3}, ProfileLevel{"high", 6},
+			ProfileLevel{"very-high", 8}, ProfileLevel{"extreme", 11})
+	},
+	"gas": func() *SensorProfile {
+		// MQ sensors need the clean air voltage measured for ppm, the voltage is published
+		return NewSensorProfile(NewLinearCalibration(5.0/1023, 0, "V"), &Range{0, 5})
+	},
+	"temperature": func() *SensorProfile {
+		c, _ := NewThermistorCalibration(DefaultThermistorBeta)
+		return NewSensorProfile(c, &Range{-40, 125})
+	},
+}
+
+// BuiltinSensorProfile returns the named built-in profile:
+// moisture, air-quality, loudness, uv, gas or temperature
+func BuiltinSensorProfile(name string) (*SensorProfile, bool) {
+	profile, found := sensorProfiles[name]
+	if !found {
+		return nil, false
+	}
+	return profile(), true
+}
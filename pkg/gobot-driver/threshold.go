@@ -0,0 +1,269 @@
+package gobot_driver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+)
+
+// Threshold events published by the Monitor on the monitored device
+const (
+	// ThresholdCrossed is published when the value enters or leaves the alarm zone, its data is ThresholdCrossing
+	ThresholdCrossed = "threshold.crossed"
+	// AlarmRaised is published when the value stayed in the alarm zone for the minimum duration, its data is Alarm
+	AlarmRaised = "alarm.raised"
+	// AlarmCleared is published when the value of the raised alarm leaves the alarm zone, its data is Alarm
+	AlarmCleared = "alarm.cleared"
+)
+
+var (
+	// ErrorInvalidThreshold is returned for thresholds which can't be monitored
+	ErrorInvalidThreshold = errors.New("invalid threshold")
+	// ErrorAlarmNotFound is returned when acknowledging the alarm which isn't raised
+	ErrorAlarmNotFound = errors.New("alarm not raised")
+)
+
+// Threshold is the named limit of the value published by the device event.
+// The value is in the alarm zone above the level, or below it when Below is set,
+// and leaves it when it gets back by more than the hysteresis
+type Threshold struct {
+	Name        string        `json:"name"`
+	Event       string        `json:"event"`
+	Level       float64       `json:"level"`
+	Below       bool          `json:"below,omitempty"`
+	Hysteresis  float64       `json:"hysteresis,omitempty"`
+	MinDuration time.Duration `json:"minDuration,omitempty"`
+}
+
+// ThresholdCrossing is the data of ThresholdCrossed event, Entered is false when the value left the alarm zone
+type ThresholdCrossing struct {
+	Threshold string  `json:"threshold"`
+	Event     string  `json:"event"`
+	Level     float64 `json:"level"`
+	Value     float64 `json:"value"`
+	Entered   bool    `json:"entered"`
+}
+
+// Alarm is the raised threshold alarm
+type Alarm struct {
+	Device       string    `json:"device"`
+	Threshold    string    `json:"threshold"`
+	Event        string    `json:"event"`
+	Level        float64   `json:"level"`
+	Value        float64   `json:"value"`
+	Raised       time.Time `json:"raised"`
+	Acknowledged bool      `json:"acknowledged"`
+}
+
+// thresholdState is the state of the single threshold
+type thresholdState struct {
+	Threshold
+	entered bool
+	value   float64
+	timer   *time.Timer
+	seq     int
+	alarm   *Alarm
+}
+
+// Monitor watches the thresholds of the device values and publishes threshold and alarm events on the device
+type Monitor struct {
+	mutex       sync.Mutex
+	thresholds  []*thresholdState
+	source      gobot.Eventer
+	device      string
+	unsubscribe func()
+}
+
+// NewMonitor creates monitor of the thresholds, threshold names should be unique
+func NewMonitor(thresholds ...Threshold) (*Monitor, error) {
+	m := &Monitor{}
+	names := map[string]bool{}
+	for _, t := range thresholds {
+		if t.Name == "" || names[t.Name] {
+			return nil, fmt.Errorf("%w: missing or duplicate name %q", ErrorInvalidThreshold, t.Name)
+		}
+		if t.Hysteresis < 0 || t.MinDuration < 0 {
+			return nil, fmt.Errorf("%w: %s hysteresis and minimum duration can't be negative", ErrorInvalidThreshold, t.Name)
+		}
+		if t.Event == "" {
+			t.Event = aio.Data
+		}
+		names[t.Name] = true
+		m.thresholds = append(m.thresholds, &thresholdState{Threshold: t})
+	}
+	return m, nil
+}
+
+// Attach starts watching the source events, the monitor can be attached to a single source
+func (m *Monitor) Attach(source gobot.Eventer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.unsubscribe != nil {
+		return
+	}
+	if d, ok := source.(interface{ Name() string }); ok {
+		m.device = d.Name()
+	}
+	source.AddEvent(ThresholdCrossed)
+	source.AddEvent(AlarmRaised)
+	source.AddEvent(AlarmCleared)
+	m.source = source
+	m.unsubscribe = subscribe(source, func(evt *gobot.Event) {
+		if value, ok := numeric(evt.Data); ok {
+			m.update(evt.Name, value)
+		}
+	})
+}
+
+// Detach stops watching the source events, raised alarms are kept
+func (m *Monitor) Detach() {
+	m.mutex.Lock()
+	unsubscribe := m.unsubscribe
+	m.unsubscribe = nil
+	m.mutex.Unlock()
+
+	// the values are updated under the mutex
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, t := range m.thresholds {
+		if t.timer != nil {
+			t.timer.Stop()
+			t.timer = nil
+		}
+	}
+}
+
+// Thresholds returns the monitored thresholds
+func (m *Monitor) Thresholds() []Threshold {
+	thresholds := make([]Threshold, 0, len(m.thresholds))
+	for _, t := range m.thresholds {
+		thresholds = append(thresholds, t.Threshold)
+	}
+	return thresholds
+}
+
+// Alarms returns the raised alarms
+func (m *Monitor) Alarms() []Alarm {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	alarms := make([]Alarm, 0)
+	for _, t := range m.thresholds {
+		if t.alarm != nil {
+			alarms = append(alarms, *t.alarm)
+		}
+	}
+	return alarms
+}
+
+// Acknowledge marks the raised alarm of the threshold as acknowledged, it stays raised until the value gets back
+func (m *Monitor) Acknowledge(threshold string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, t := range m.thresholds {
+		if t.Name == threshold && t.alarm != nil {
+			t.alarm.Acknowledged = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrorAlarmNotFound, threshold)
+}
+
+// update applies the value of the event to its thresholds
+func (m *Monitor) update(event string, value float64) {
+	type publication struct {
+		name string
+		data interface{}
+	}
+	var published []publication
+
+	m.mutex.Lock()
+	source := m.source
+	for _, t := range m.thresholds {
+		if t.Event != event {
+			continue
+		}
+		t.value = value
+		switch {
+		case !t.entered && t.beyond(value, 0):
+			t.entered = true
+			published = append(published, publication{ThresholdCrossed, t.crossing(value)})
+			if t.MinDuration == 0 {
+				published = append(published, publication{AlarmRaised, m.raise(t)})
+			} else {
+				t.seq++
+				seq := t.seq
+				t.timer = time.AfterFunc(t.MinDuration, func() { m.expire(t, seq) })
+			}
+		case t.entered && !t.beyond(value, -t.Hysteresis):
+			t.entered = false
+			if t.timer != nil {
+				t.timer.Stop()
+				t.timer = nil
+			}
+			published = append(published, publication{ThresholdCrossed, t.crossing(value)})
+			if t.alarm != nil {
+				alarm := *t.alarm
+				alarm.Value = value
+				t.alarm = nil
+				published = append(published, publication{AlarmCleared, alarm})
+			}
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, p := range published {
+		source.Publish(p.name, p.data)
+	}
+}
+
+// expire raises the alarm when the value is still in the alarm zone after the minimum duration
+func (m *Monitor) expire(t *thresholdState, seq int) {
+	m.mutex.Lock()
+	if !t.entered || t.timer == nil || seq != t.seq {
+		m.mutex.Unlock()
+		return
+	}
+	t.timer = nil
+	alarm := m.raise(t)
+	source := m.source
+	m.mutex.Unlock()
+
+	source.Publish(AlarmRaised, alarm)
+}
+
+// raise creates the alarm of the threshold, the caller must hold the mutex
+func (m *Monitor) raise(t *thresholdState) Alarm {
+	t.alarm = &Alarm{
+		Device:    m.device,
+		Threshold: t.Name,
+		Event:     t.Event,
+		Level:     t.Level,
+		Value:     t.value,
+		Raised:    time.Now(),
+	}
+	return *t.alarm
+}
+
+// beyond returns true when the value is in the alarm zone of the level moved by the margin
+func (t *thresholdState) beyond(value, margin float64) bool {
+	if t.Below {
+		return value < t.Level-margin
+	}
+	return value > t.Level+margin
+}
+
+func (t *thresholdState) crossing(value float64) ThresholdCrossing {
+	return ThresholdCrossing{Threshold: t.Name, Event: t.Event, Level: t.Level, Value: value, Entered: t.entered}
+}
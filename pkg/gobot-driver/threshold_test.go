@@ -0,0 +1,79 @@
+package gobot_driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gobot.io/x/gobot"
+)
+
+func TestMonitorHysteresis(t *testing.T) {
+	m, err := NewMonitor(
+		Threshold{Name: "high", Event: Temperature, Level: 30, Hysteresis: 1},
+		Threshold{Name: "low", Event: Temperature, Level: 25, Below: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := gobot.NewEventer()
+	m.Attach(source)
+	defer m.Detach()
+
+	events := make(chan string, 10)
+	unsubscribe := subscribe(source, func(evt *gobot.Event) { events <- evt.Name })
+	defer unsubscribe()
+
+	m.update(Temperature, 31)
+	if alarms := m.Alarms(); len(alarms) != 1 || alarms[0].Threshold != "high" || alarms[0].Value != 31 {
+		t.Fatalf("expected high alarm, got %+v", alarms)
+	}
+	m.update(Temperature, 29.5)
+	if len(m.Alarms()) != 1 {
+		t.Error("alarm within hysteresis should stay raised")
+	}
+	if err := m.Acknowledge("high"); err != nil || !m.Alarms()[0].Acknowledged {
+		t.Errorf("expected acknowledged alarm, got %v", err)
+	}
+	m.update(Temperature, 29)
+	if len(m.Alarms()) != 0 {
+		t.Error("alarm should be cleared")
+	}
+	if err := m.Acknowledge("high"); !errors.Is(err, ErrorAlarmNotFound) {
+		t.Errorf("expected alarm not found, got %v", err)
+	}
+
+	want := []string{ThresholdCrossed, AlarmRaised, ThresholdCrossed, AlarmCleared}
+	for _, name := range want {
+		select {
+		case got := <-events:
+			if got != name {
+				t.Errorf("expected %s event, got %s", name, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s event not published", name)
+		}
+	}
+}
+
+func TestMonitorMinDuration(t *testing.T) {
+	m, _ := NewMonitor(Threshold{Name: "high", Level: 500, MinDuration: 30 * time.Millisecond})
+	m.Attach(gobot.NewEventer())
+	defer m.Detach()
+
+	m.update("data", 600)
+	m.update("data", 400)
+	m.update("data", 600)
+	time.Sleep(20 * time.Millisecond)
+	if len(m.Alarms()) != 0 {
+		t.Error("alarm raised before minimum duration")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(m.Alarms()) != 1 {
+		t.Error("alarm not raised after minimum duration")
+	}
+
+	if _, err := NewMonitor(Threshold{Name: "a"}, Threshold{Name: "a"}); !errors.Is(err, ErrorInvalidThreshold) {
+		t.Errorf("expected duplicate name error, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package platform
+
+import (
+	"fmt"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/aio"
+	"gobot.io/x/gobot/drivers/gpio"
+	"gobot.io/x/gobot/drivers/i2c"
+)
+
+// LED returns the LED device with the given name
+func (p *GrovePi) LED(name string) (*gpio.GroveLedDriver, error) {
+	d, err := p.deviceOf(name, GrovePiLEDDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*gpio.GroveLedDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiLEDDriverName)
+}
+
+// Button returns the button device with the given name configured in polling mode
+func (p *GrovePi) Button(name string) (*gpio.GroveButtonDriver, error) {
+	d, err := p.deviceOf(name, GrovePiButtonDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*gpio.GroveButtonDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiButtonDriverName)
+}
+
+// LatchedButton returns the button device with the given name configured in latched mode
+func (p *GrovePi) LatchedButton(name string) (*driver.GroveLatchedButtonDriver, error) {
+	d, err := p.deviceOf(name, GrovePiButtonDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveLatchedButtonDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiButtonDriverName)
+}
+
+// Buzzer returns the buzzer device with the given name
+func (p *GrovePi) Buzzer(name string) (*driver.GroveBuzzerDriver, error) {
+	d, err := p.deviceOf(name, GrovePiBuzzerDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveBuzzerDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiBuzzerDriverName)
+}
+
+// Rotary returns the rotary angle sensor device with the given name
+func (p *GrovePi) Rotary(name string) (*aio.GroveRotaryDriver, error) {
+	d, err := p.deviceOf(name, GrovePiRotarySensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*aio.GroveRotaryDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiRotarySensorDriverName)
+}
+
+// SoundSensor returns the sound sensor device with the given name
+func (p *GrovePi) SoundSensor(name string) (*aio.GroveSoundSensorDriver, error) {
+	d, err := p.deviceOf(name, GrovePiSoundSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*aio.GroveSoundSensorDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiSoundSensorDriverName)
+}
+
+// LightSensor returns the light sensor device with the given name
+func (p *GrovePi) LightSensor(name string) (*aio.GroveLightSensorDriver, error) {
+	d, err := p.deviceOf(name, GrovePiLightSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*aio.GroveLightSensorDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiLightSensorDriverName)
+}
+
+// LCD returns the RGB LCD panel device with the given name
+func (p *GrovePi) LCD(name string) (*i2c.GroveLcdDriver, error) {
+	d, err := p.deviceOf(name, GrovePiRGBLCDPanelDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*i2c.GroveLcdDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiRGBLCDPanelDriverName)
+}
+
+// DHT returns the temperature and humidity sensor device with the given name
+func (p *GrovePi) DHT(name string) (*driver.GroveTemperatureAndHumidityDriver, error) {
+	d, err := p.deviceOf(name, GrovePiDHTSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveTemperatureAndHumidityDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiDHTSensorDriverName)
+}
+
+// UltrasonicRanger returns the ultrasonic ranger device with the given name
+func (p *GrovePi) UltrasonicRanger(name string) (*driver.GroveUltrasonicRangerDriver, error) {
+	d, err := p.deviceOf(name, GrovePiUltrasonicRangerDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveUltrasonicRangerDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiUltrasonicRangerDriverName)
+}
+
+// FourDigitDisplay returns the 4-digit display device with the given name
+func (p *GrovePi) FourDigitDisplay(name string) (*driver.Grove4DigitDisplayDriver, error) {
+	d, err := p.deviceOf(name, GrovePi4DigitDisplayDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.Grove4DigitDisplayDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePi4DigitDisplayDriverName)
+}
+
+// LedBar returns the LED bar device with the given name
+func (p *GrovePi) LedBar(name string) (*driver.GroveLedBarDriver, error) {
+	d, err := p.deviceOf(name, GrovePiLedBarDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveLedBarDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiLedBarDriverName)
+}
+
+// ChainableRGB returns the chainable RGB LED device with the given name
+func (p *GrovePi) ChainableRGB(name string) (*driver.GroveChainableRGBDriver, error) {
+	d, err := p.deviceOf(name, GrovePiChainableRGBDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveChainableRGBDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiChainableRGBDriverName)
+}
+
+// IRReceiver returns the IR receiver device with the given name
+func (p *GrovePi) IRReceiver(name string) (*driver.GroveIRReceiverDriver, error) {
+	d, err := p.deviceOf(name, GrovePiIRReceiverDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveIRReceiverDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiIRReceiverDriverName)
+}
+
+// Encoder returns the encoder device with the given name
+func (p *GrovePi) Encoder(name string) (*driver.GroveEncoderDriver, error) {
+	d, err := p.deviceOf(name, GrovePiEncoderDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveEncoderDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiEncoderDriverName)
+}
+
+// DustSensor returns the dust sensor device with the given name
+func (p *GrovePi) DustSensor(name string) (*driver.GroveDustSensorDriver, error) {
+	d, err := p.deviceOf(name, GrovePiDustSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveDustSensorDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiDustSensorDriverName)
+}
+
+// FlowSensor returns the flow sensor device with the given name
+func (p *GrovePi) FlowSensor(name string) (*driver.GroveFlowSensorDriver, error) {
+	d, err := p.deviceOf(name, GrovePiFlowSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveFlowSensorDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiFlowSensorDriverName)
+}
+
+// PIRMotion returns the PIR motion sensor device with the given name
+func (p *GrovePi) PIRMotion(name string) (*driver.GrovePIRMotionDriver, error) {
+	d, err := p.deviceOf(name, GrovePiPIRMotionDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GrovePIRMotionDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiPIRMotionDriverName)
+}
+
+// Servo returns the servo device with the given name
+func (p *GrovePi) Servo(name string) (*driver.GroveServoDriver, error) {
+	d, err := p.deviceOf(name, GrovePiServoDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveServoDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiServoDriverName)
+}
+
+// Relay returns the relay device with the given name
+func (p *GrovePi) Relay(name string) (*driver.GroveRelayDriver, error) {
+	d, err := p.deviceOf(name, GrovePiRelayDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveRelayDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiRelayDriverName)
+}
+
+// MOSFET returns the MOSFET device with the given name
+func (p *GrovePi) MOSFET(name string) (*driver.GroveMOSFETDriver, error) {
+	d, err := p.deviceOf(name, GrovePiMOSFETDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveMOSFETDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiMOSFETDriverName)
+}
+
+// AnalogSensor returns the analog sensor device with the given name
+func (p *GrovePi) AnalogSensor(name string) (*driver.GroveAnalogSensorDriver, error) {
+	d, err := p.deviceOf(name, GrovePiAnalogSensorDriverName)
+	if err != nil {
+		return nil, err
+	}
+	if drv, ok := d.(*driver.GroveAnalogSensorDriver); ok {
+		return drv, nil
+	}
+	return nil, typeMismatch(name, d, GrovePiAnalogSensorDriverName)
+}
+
+// deviceOf returns the device with the given name checking it's configured with the expected driver
+func (p *GrovePi) deviceOf(name, driverName string) (gobot.Device, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	d, found := p.devicesByName[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %q", ErrorDeviceNotFound, name)
+	}
+	if cfg := p.deviceConfigs[name]; cfg != nil && cfg.Driver != driverName {
+		return nil, fmt.Errorf("%w: %q is %s, not %s", ErrorDeviceTypeMismatch, name, cfg.Driver, driverName)
+	}
+	return d, nil
+}
+
+func typeMismatch(name string, d gobot.Device, driverName string) error {
+	return fmt.Errorf("%w: %q is %T, not %s", ErrorDeviceTypeMismatch, name, d, driverName)
+}
@@ -0,0 +1,163 @@
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"gobot-grovepi-platform/pkg/config"
+	"gobot.io/x/gobot/api"
+	"net/http"
+)
+
+const persistParamName = "persist"
+
+func (m *Master) addRoutes(a *api.API) {
+	a.Post("/api/platforms/reload", m.reload)
+	a.Post("/api/platforms/save", m.save)
+	a.Get("/api/platforms/:platform/devices", m.devices)
+	a.Post("/api/platforms/:platform/devices", m.addDevice)
+	a.Get("/api/platforms/:platform/devices/:device", m.device)
+	a.Delete("/api/platforms/:platform/devices/:device", m.removeDevice)
+	a.Get("/api/platforms/:platform/alarms", m.alarms)
+	a.Post("/api/platforms/:platform/alarms/:device/:threshold/acknowledge", m.acknowledgeAlarm)
+}
+
+func (m *Master) reload(res http.ResponseWriter, req *http.Request) {
+	if err := m.ReloadFromFile(); err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(res, http.StatusOK, map[string]interface{}{"status": "reloaded"})
+}
+
+func (m *Master) save(res http.ResponseWriter, req *http.Request) {
+	if err := m.Save(); err != nil {
+		writeError(res, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(res, http.StatusOK, map[string]interface{}{"status": "saved"})
+}
+
+func (m *Master) devices(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(res, http.StatusOK, p.DeviceConfigs())
+}
+
+func (m *Master) device(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+	name := req.URL.Query().Get(":device")
+	for _, cfg := range p.DeviceConfigs() {
+		if cfg.Name == name {
+			writeJSON(res, http.StatusOK, cfg)
+			return
+		}
+	}
+	writeError(res, http.StatusNotFound, ErrorDeviceNotFound)
+}
+
+func (m *Master) addDevice(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+
+	cfg := config.NewDeviceConfig()
+	if err := json.NewDecoder(req.Body).Decode(cfg); err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := p.AddDevice(cfg); err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+	if !m.persist(res, req) {
+		// the device isn't kept when it can't be saved, so the request can be retried
+		_ = p.RemoveDevice(cfg.Name)
+		return
+	}
+	writeJSON(res, http.StatusCreated, cfg)
+}
+
+func (m *Master) removeDevice(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+
+	if err := p.RemoveDevice(req.URL.Query().Get(":device")); err != nil {
+		switch {
+		case errors.Is(err, ErrorDeviceNotFound):
+			writeError(res, http.StatusNotFound, err)
+		case errors.Is(err, ErrorDeviceInUse):
+			writeError(res, http.StatusConflict, err)
+		default:
+			writeError(res, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	if !m.persist(res, req) {
+		return
+	}
+	writeJSON(res, http.StatusOK, map[string]interface{}{"status": "removed"})
+}
+
+func (m *Master) alarms(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(res, http.StatusOK, p.Alarms())
+}
+
+func (m *Master) acknowledgeAlarm(res http.ResponseWriter, req *http.Request) {
+	p, err := m.platform(req.URL.Query().Get(":platform"))
+	if err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+
+	query := req.URL.Query()
+	if err := p.AcknowledgeAlarm(query.Get(":device"), query.Get(":threshold")); err != nil {
+		writeError(res, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(res, http.StatusOK, map[string]interface{}{"status": "acknowledged"})
+}
+
+// persist saves the config when requested by the persist query parameter,
+// returns false when the error response has been written
+func (m *Master) persist(res http.ResponseWriter, req *http.Request) bool {
+	if req.URL.Query().Get(persistParamName) != "true" {
+		return true
+	}
+	if err := m.Save(); err != nil {
+		writeError(res, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+func writeError(res http.ResponseWriter, status int, err error) {
+	writeJSON(res, status, map[string]interface{}{"error": err.Error()})
+}
+
+func writeJSON(res http.ResponseWriter, status int, j interface{}) {
+	data, err := json.Marshal(j)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", "application/json; charset=utf-8")
+	res.WriteHeader(status)
+	_, _ = res.Write(data)
+}
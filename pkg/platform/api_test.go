@@ -0,0 +1,84 @@
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMasterAddDeviceInvalidDuration(t *testing.T) {
+	p := newTestPlatform(t)
+	m := &Master{platforms: []*GrovePi{p}}
+
+	for driverName, pin := range map[string]string{
+		GrovePiButtonDriverName:           "D2",
+		GrovePiDHTSensorDriverName:        "D4",
+		GrovePiLightSensorDriverName:      "A0",
+		GrovePiRotarySensorDriverName:     "A1",
+		GrovePiSoundSensorDriverName:      "A2",
+		GrovePiUltrasonicRangerDriverName: "D7",
+	} {
+		body := `{"name": "sensor", "driver": "` + driverName + `", "pin": "` + pin + `", "config": {"samplingInterval": 100}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/platforms/test/devices?:platform=test", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		m.addDevice(res, req)
+
+		if res.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected bad request, got %d %s", driverName, res.Code, res.Body)
+			continue
+		}
+		var reply map[string]interface{}
+		if err := json.Unmarshal(res.Body.Bytes(), &reply); err != nil || !strings.Contains(reply["error"].(string), ErrorInvalidProperty.Error()) {
+			t.Errorf("%s: expected invalid property, got %s", driverName, res.Body)
+		}
+	}
+	if len(p.DeviceConfigs()) != 0 {
+		t.Errorf("invalid devices shouldn't be added, got %v", p.DeviceConfigs())
+	}
+}
+
+func TestMasterAddDeviceNotPersisted(t *testing.T) {
+	p := newTestPlatform(t)
+	m := &Master{platforms: []*GrovePi{p}}
+
+	body := `{"name": "led", "driver": "` + GrovePiLEDDriverName + `", "pin": "D3"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/platforms/test/devices?:platform=test&persist=true", strings.NewReader(body))
+	res := httptest.NewRecorder()
+	m.addDevice(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Errorf("expected save error, got %d %s", res.Code, res.Body)
+	}
+	if _, err := p.Device("led"); !errors.Is(err, ErrorDeviceNotFound) {
+		t.Errorf("device should be removed when it isn't saved, got %v", err)
+	}
+}
+
+func TestMasterRemoveDeviceStatus(t *testing.T) {
+	bar := newTestDeviceConfig("bar", GrovePiLedBarDriverName, "D5")
+	bar.Properties = map[string]interface{}{SourcePropertyName: "light"}
+	p := newTestPlatform(t, newTestDeviceConfig("light", GrovePiLightSensorDriverName, "A0"), bar)
+	m := &Master{platforms: []*GrovePi{p}}
+
+	for _, tt := range []struct {
+		device string
+		status int
+	}{
+		{"dht", http.StatusNotFound},
+		{"light", http.StatusConflict},
+		{"bar", http.StatusOK},
+		{"light", http.StatusOK},
+	} {
+		device, status := tt.device, tt.status
+		req := httptest.NewRequest(http.MethodDelete, "/api/platforms/test/devices/"+device+"?:platform=test&:device="+device, nil)
+		res := httptest.NewRecorder()
+		m.removeDevice(res, req)
+
+		if res.Code != status {
+			t.Errorf("%s: expected status %d, got %d %s", device, status, res.Code, res.Body)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package platform
+
+import (
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	"gobot.io/x/gobot"
+)
+
+// AddDevice creates the device and adds it to the platform, the device is started when the platform is running.
+// The device config is added to the platform config, see Master.Save to persist it
+func (p *GrovePi) AddDevice(cfg *config.DeviceConfig) (gobot.Device, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.robot == nil {
+		return nil, ErrorNotInitialized
+	}
+
+	devices, err := p.createDevices(p.grovepi, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, ErrorDriverNotSupported
+	}
+	d := devices[0]
+
+	if err := bindDevices(p.devicesByName, map[string]*config.DeviceConfig{cfg.Name: cfg}); err != nil {
+		p.forgetDevice(cfg.Name)
+		return nil, err
+	}
+
+	if p.robot.Running() {
+		if err := d.Start(); err != nil {
+			p.forgetDevice(cfg.Name)
+			return nil, err
+		}
+	}
+
+	p.conf.Devices = append(p.conf.Devices, cfg)
+	p.attachPipelines(nil)
+	p.attachMonitors(nil)
+	p.attachGestures(nil)
+	p.updateRobotDevices()
+	return d, nil
+}
+
+// RemoveDevice halts the device and removes it from the platform and the platform config,
+// devices bound to other devices by their config can't be removed
+func (p *GrovePi) RemoveDevice(name string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.robot == nil {
+		return ErrorNotInitialized
+	}
+	d, found := p.devicesByName[name]
+	if !found {
+		return ErrorDeviceNotFound
+	}
+	for other, cfg := range p.deviceConfigs {
+		if other != name && referencesDevice(cfg, name) {
+			return fmt.Errorf("%w: %s is bound to %s", ErrorDeviceInUse, other, name)
+		}
+	}
+
+	if p.robot.Running() {
+		if err := d.Halt(); err != nil {
+			return err
+		}
+	}
+
+	if a, ok := d.(interlocked); ok {
+		if err := a.SetInterlock(nil); err != nil {
+			return err
+		}
+	}
+	p.forgetDevice(name)
+
+	devices := make([]*config.DeviceConfig, 0, len(p.conf.Devices))
+	for _, cfg := range p.conf.Devices {
+		if cfg.Name != name {
+			devices = append(devices, cfg)
+		}
+	}
+	p.conf.Devices = devices
+	p.updateRobotDevices()
+	return nil
+}
+
+// Device returns the device by its name
+func (p *GrovePi) Device(name string) (gobot.Device, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if d, found := p.devicesByName[name]; found {
+		return d, nil
+	}
+	return nil, ErrorDeviceNotFound
+}
+
+// Devices returns the platform devices in configuration order
+func (p *GrovePi) Devices() []gobot.Device {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	devices := make([]gobot.Device, 0, len(p.devicesByName))
+	if p.conf == nil {
+		return devices
+	}
+	for _, cfg := range p.conf.Devices {
+		if d, found := p.devicesByName[cfg.Name]; found {
+			devices = append(devices, d)
+		}
+	}
+	return devices
+}
+
+// DeviceConfigs returns configs of the platform devices in configuration order
+func (p *GrovePi) DeviceConfigs() []*config.DeviceConfig {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conf == nil {
+		return []*config.DeviceConfig{}
+	}
+	return append([]*config.DeviceConfig{}, p.conf.Devices...)
+}
+
+// referencesDevice returns true when the device config binds the device to the named one, see deviceBinders
+func referencesDevice(cfg *config.DeviceConfig, name string) bool {
+	var property string
+	switch cfg.Driver {
+	case GrovePiLedBarDriverName:
+		property = SourcePropertyName
+	case GrovePiUltrasonicRangerDriverName:
+		property = TemperaturePropertyName
+	default:
+		return false
+	}
+	source, ok := cfg.Properties[property].(string)
+	return ok && source == name
+}
+
+// forgetDevice drops the device from the platform indexes, the caller must hold the mutex
+func (p *GrovePi) forgetDevice(name string) {
+	if cfg, found := p.deviceConfigs[name]; found {
+		delete(p.devicesByPin, cfg.Pin)
+	}
+	if pipeline, found := p.pipelines[name]; found {
+		pipeline.Detach()
+	}
+	if monitor, found := p.monitors[name]; found {
+		monitor.Detach()
+	}
+	if gestures, found := p.gestures[name]; found {
+		gestures.Detach()
+	}
+	delete(p.devicesByName, name)
+	delete(p.deviceConfigs, name)
+	delete(p.pipelines, name)
+	delete(p.monitors, name)
+	delete(p.gestures, name)
+}
@@ -0,0 +1,171 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"gobot.io/x/gobot/drivers/aio"
+	"time"
+)
+
+// Filter types of the filters property
+const (
+	MovingAverageFilter = "movingAverage"
+	MedianFilter        = "median"
+	ExponentialFilter   = "exponential"
+	DeadbandFilter      = "deadband"
+	RateLimitFilter     = "rateLimit"
+	OutlierFilter       = "outlier"
+)
+
+// filteredDrivers are the polling drivers supporting the filters property
+var filteredDrivers = map[string]bool{
+	GrovePiLightSensorDriverName:      true,
+	GrovePiSoundSensorDriverName:      true,
+	GrovePiRotarySensorDriverName:     true,
+	GrovePiUltrasonicRangerDriverName: true,
+	GrovePiDHTSensorDriverName:        true,
+	GrovePiAnalogSensorDriverName:     true,
+}
+
+// analogReader returns the reader of the gobot analog drivers, filtered when the device has filters
+func analogReader(gp *driver.GrovePiDriver, cfg *config.DeviceConfig) (aio.AnalogReader, error) {
+	filters, err := filtersProperty(cfg)
+	if err != nil || filters == nil {
+		return gp, err
+	}
+	return driver.NewFilteredAnalogReader(gp, filters), nil
+}
+
+// filtersProperty parses the filter chain applied in the listed order, e.g.
+//   filters:
+//     - {type: outlier, maxDeviation: 50, size: 5}
+//     - {type: median, size: 5}
+//     - {type: movingAverage, size: 4}
+//     - {type: exponential, alpha: 0.3}
+//     - {type: deadband, band: 2}
+//     - {type: rateLimit, interval: 1s}
+func filtersProperty(cfg *config.DeviceConfig) (driver.FilterChain, error) {
+	v, found := cfg.Properties[FiltersPropertyName]
+	if !found {
+		return nil, nil
+	}
+	return parseFilterChain(cfg, FiltersPropertyName, v)
+}
+
+// dhtFiltersProperty parses the filter chains of the temperature and humidity, a list applies to both of them,
+// a mapping sets separate lists under the temperature and humidity keys
+func dhtFiltersProperty(cfg *config.DeviceConfig) (temperature, humidity driver.FilterChain, err error) {
+	v, found := cfg.Properties[FiltersPropertyName]
+	if !found {
+		return nil, nil, nil
+	}
+	m, ok := stringMap(v)
+	if !ok {
+		// every chain keeps its own state, so the list is parsed for each of them
+		if temperature, err = parseFilterChain(cfg, FiltersPropertyName, v); err != nil {
+			return nil, nil, err
+		}
+		humidity, err = parseFilterChain(cfg, FiltersPropertyName, v)
+		return temperature, humidity, err
+	}
+
+	for _, key := range sortedKeys(m) {
+		name := FiltersPropertyName + "." + key
+		switch key {
+		case driver.Temperature:
+			temperature, err = parseFilterChain(cfg, name, m[key])
+		case driver.Humidity:
+			humidity, err = parseFilterChain(cfg, name, m[key])
+		default:
+			err = fmt.Errorf("%w: %s of %s should be %s or %s", ErrorInvalidProperty, name, cfg.Name,
+				driver.Temperature, driver.Humidity)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return temperature, humidity, nil
+}
+
+// parseFilterChain parses the list of filters of the named property
+func parseFilterChain(cfg *config.DeviceConfig, name string, v interface{}) (driver.FilterChain, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s of %s should be a list", ErrorInvalidProperty, name, cfg.Name)
+	}
+
+	chain := make(driver.FilterChain, 0, len(list))
+	for i, item := range list {
+		f, err := parseFilter(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: filter %d of %s: %v", ErrorInvalidProperty, i+1, cfg.Name, err)
+		}
+		chain = append(chain, f)
+	}
+	return chain, nil
+}
+
+func parseFilter(v interface{}) (driver.Filter, error) {
+	m, ok := stringMap(v)
+	if !ok {
+		return nil, fmt.Errorf("%T isn't a mapping", v)
+	}
+	size := func(def int) (int, error) {
+		n, ok := number(valueOr(m["size"], float64(def)))
+		if !ok {
+			return 0, errors.New("size should be a number")
+		}
+		return int(n), nil
+	}
+
+	switch m["type"] {
+	case MovingAverageFilter:
+		n, err := size(5)
+		if err != nil {
+			return nil, err
+		}
+		return driver.NewMovingAverageFilter(n)
+	case MedianFilter:
+		n, err := size(5)
+		if err != nil {
+			return nil, err
+		}
+		return driver.NewMedianFilter(n)
+	case ExponentialFilter:
+		alpha, ok := number(m["alpha"])
+		if !ok {
+			return nil, errors.New("alpha should be a number")
+		}
+		return driver.NewExponentialFilter(alpha)
+	case DeadbandFilter:
+		band, ok := number(m["band"])
+		if !ok {
+			return nil, errors.New("band should be a number")
+		}
+		return driver.NewDeadbandFilter(band)
+	case RateLimitFilter:
+		s, ok := m["interval"].(string)
+		if !ok {
+			return nil, errors.New("interval should be a duration")
+		}
+		interval, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, err
+		}
+		return driver.NewRateLimitFilter(interval)
+	case OutlierFilter:
+		maxDeviation, ok := number(m["maxDeviation"])
+		if !ok {
+			return nil, errors.New("maxDeviation should be a number")
+		}
+		n, err := size(5)
+		if err != nil {
+			return nil, err
+		}
+		return driver.NewOutlierFilter(maxDeviation, n)
+	default:
+		return nil, fmt.Errorf("unknown type %v", m["type"])
+	}
+}
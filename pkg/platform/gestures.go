@@ -0,0 +1,83 @@
+package platform
+
+import (
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"gobot.io/x/gobot"
+	"time"
+)
+
+// newGestures creates the gesture recognizer of the device, buttons have one with the default timings
+// unless the gestures property is false, other digital inputs need the property
+func newGestures(d gobot.Device, cfg *config.DeviceConfig) (*driver.Gestures, error) {
+	v, found := cfg.Properties[GesturesPropertyName]
+	if !found && cfg.Driver != GrovePiButtonDriverName {
+		return nil, nil
+	}
+	if enabled, ok := v.(bool); ok && !enabled {
+		return nil, nil
+	}
+	if _, ok := d.(gobot.Eventer); !ok {
+		return nil, fmt.Errorf("%w: %s doesn't publish events", ErrorInvalidProperty, cfg.Name)
+	}
+
+	g, err := gesturesProperty(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, GesturesPropertyName, cfg.Name, err)
+	}
+	return g, nil
+}
+
+// gesturesProperty parses the gesture timings and the input events, e.g.
+//   gestures: true
+//   gestures: {doubleClick: 250ms, longPress: 1s, repeat: 100ms}
+//   gestures: {press: motion, release: no-motion, doubleClick: 0s}
+func gesturesProperty(v interface{}) (*driver.Gestures, error) {
+	timings := driver.DefaultGestureTimings
+	if v == nil || v == true {
+		return driver.NewGestures(timings, "", "")
+	}
+
+	m, ok := stringMap(v)
+	if !ok {
+		return nil, fmt.Errorf("%T is neither boolean nor mapping", v)
+	}
+	for name, t := range map[string]*time.Duration{
+		"doubleClick": &timings.DoubleClick,
+		"longPress":   &timings.LongPress,
+		"repeat":      &timings.Repeat,
+	} {
+		s, found := m[name]
+		if !found {
+			continue
+		}
+		str, ok := s.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s should be a duration", name)
+		}
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, err
+		}
+		*t = d
+	}
+	press, _ := m["press"].(string)
+	release, _ := m["release"].(string)
+	return driver.NewGestures(timings, press, release)
+}
+
+// attachGestures attaches the gesture recognizers to their devices and detaches the old ones which aren't used anymore,
+// the caller must hold the mutex
+func (p *GrovePi) attachGestures(old map[string]*driver.Gestures) {
+	for name, g := range p.gestures {
+		if source, ok := p.devicesByName[name].(gobot.Eventer); ok {
+			g.Attach(source)
+		}
+	}
+	for name, g := range old {
+		if p.gestures[name] != g {
+			g.Detach()
+		}
+	}
+}
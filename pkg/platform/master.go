@@ -0,0 +1,326 @@
+package platform
+
+import (
+	"gobot-grovepi-platform/pkg/config"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/api"
+	"gobot.io/x/gobot/platforms/raspi"
+	"strings"
+	"sync"
+)
+
+// Master runs every GrovePi platform as a separate robot under single gobot.Master
+type Master struct {
+	mutex     sync.Mutex
+	platforms []*GrovePi
+	adaptors  map[int]*raspi.Adaptor
+	conf      *config.AppConfig
+}
+
+// DeviceNameSeparator separates platform and device names in qualified device names, e.g. "board1/dht"
+const DeviceNameSeparator = "/"
+
+var (
+	masterOnce     sync.Once
+	masterInstance *Master
+)
+
+func GetMaster() *Master {
+	masterOnce.Do(func() {
+		masterInstance = &Master{
+			platforms: []*GrovePi{},
+		}
+	})
+	return masterInstance
+}
+
+// Init initializes the platform of every platform config,
+// unnamed platforms are given RobotDefaultName
+func (m *Master) Init(conf *config.AppConfig) error {
+	if err := setProfiles(conf.Profiles); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	m.conf = conf
+	m.mutex.Unlock()
+
+	for _, pc := range conf.AllPlatforms() {
+		name := pc.Name
+		if name == "" {
+			name = RobotDefaultName
+		}
+		if err := m.platformOrAdd(name).Init(pc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Platform returns the added platform with the given name, initialized or not
+func (m *Master) Platform(name string) (*GrovePi, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if p := m.find(name); p != nil {
+		return p, nil
+	}
+	return nil, ErrorPlatformNotFound
+}
+
+// AddPlatform adds a new platform with the given name, it's started by Run once it's initialized
+func (m *Master) AddPlatform(name string) (*GrovePi, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.find(name) != nil {
+		return nil, ErrorNameAlreadyInUse
+	}
+	p := newGrovePi(name, m)
+	m.platforms = append(m.platforms, p)
+	return p, nil
+}
+
+// platformOrAdd returns the platform with the given name, it's added when it doesn't exist yet
+func (m *Master) platformOrAdd(name string) *GrovePi {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if p := m.find(name); p != nil {
+		return p
+	}
+	p := newGrovePi(name, m)
+	m.platforms = append(m.platforms, p)
+	return p
+}
+
+// find returns the platform with the given name or nil, the caller holds mutex
+func (m *Master) find(name string) *GrovePi {
+	for _, p := range m.platforms {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Platforms returns the initialized platforms
+func (m *Master) Platforms() []*GrovePi {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	platforms := make([]*GrovePi, 0, len(m.platforms))
+	for _, p := range m.platforms {
+		if robot, _, _ := p.state(); robot != nil {
+			platforms = append(platforms, p)
+		}
+	}
+	return platforms
+}
+
+// Device returns the device by its qualified name "platform/device",
+// plain device name is looked up in the default platform
+func (m *Master) Device(name string) (gobot.Device, error) {
+	platformName, deviceName := RobotDefaultName, name
+	if i := strings.Index(name, DeviceNameSeparator); i >= 0 {
+		platformName, deviceName = name[:i], name[i+len(DeviceNameSeparator):]
+	}
+
+	p, err := m.platform(platformName)
+	if err != nil {
+		return nil, err
+	}
+	return p.Device(deviceName)
+}
+
+// Reload applies the device configuration of every platform, see GrovePi.Reload.
+// The new configuration is applied to all platforms or none of them, when a platform
+// fails to reload the platforms and the sensor profiles are left as they were.
+// Platforms can't be added or removed without restart
+func (m *Master) Reload(conf *config.AppConfig) error {
+	configs := conf.AllPlatforms()
+	byPlatform := make(map[*GrovePi]*config.GrovePiConfig, len(configs))
+	for _, pc := range configs {
+		name := pc.Name
+		if name == "" {
+			name = RobotDefaultName
+		}
+		p, err := m.platform(name)
+		if err != nil {
+			return err
+		}
+		if _, duplicate := byPlatform[p]; duplicate {
+			return ErrorNameAlreadyInUse
+		}
+		byPlatform[p] = pc
+	}
+
+	// platforms are locked in the order of Save
+	m.mutex.Lock()
+	platforms := make([]*GrovePi, 0, len(byPlatform))
+	for _, p := range m.platforms {
+		if _, found := byPlatform[p]; found {
+			platforms = append(platforms, p)
+		}
+	}
+	m.mutex.Unlock()
+	for _, p := range platforms {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+	}
+
+	previous := profiles()
+	if err := setProfiles(conf.Profiles); err != nil {
+		return err
+	}
+
+	reloads := make([]*reload, 0, len(platforms))
+	rollback := func() {
+		for i := len(reloads) - 1; i >= 0; i-- {
+			reloads[i].rollback()
+		}
+		_ = setProfiles(previous)
+	}
+	for _, p := range platforms {
+		r, err := p.prepareReload(byPlatform[p])
+		if err != nil {
+			rollback()
+			return err
+		}
+		reloads = append(reloads, r)
+	}
+	for _, r := range reloads {
+		if err := r.swap(); err != nil {
+			rollback()
+			return err
+		}
+	}
+	for _, r := range reloads {
+		r.commit()
+	}
+
+	m.mutex.Lock()
+	m.conf = conf
+	m.mutex.Unlock()
+	return nil
+}
+
+// ReloadFromFile reloads the config file the platforms were initialized from
+func (m *Master) ReloadFromFile() error {
+	m.mutex.Lock()
+	if m.conf == nil {
+		m.mutex.Unlock()
+		return ErrorNoConfigFile
+	}
+	confFile := m.conf.File()
+	m.mutex.Unlock()
+
+	conf, err := config.LoadFromFile(confFile)
+	if err != nil {
+		return err
+	}
+	return m.Reload(conf)
+}
+
+// Save writes the current configuration, including devices added or removed at runtime,
+// back to the config file the platforms were initialized from
+func (m *Master) Save() error {
+	m.mutex.Lock()
+	conf := m.conf
+	platforms := append([]*GrovePi{}, m.platforms...)
+	m.mutex.Unlock()
+
+	if conf == nil || conf.File() == "" {
+		return ErrorNoConfigFile
+	}
+
+	for _, p := range platforms {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+	}
+	return conf.Save()
+}
+
+// Run starts all initialized platforms and the API
+func (m *Master) Run() error {
+	platforms := m.Platforms()
+	if len(platforms) == 0 {
+		return ErrorNotInitialized
+	}
+
+	mbot := gobot.NewMaster()
+	for _, p := range platforms {
+		robot, _, _ := p.state()
+		mbot.AddRobot(robot)
+	}
+
+	a := api.NewAPI(mbot)
+	a.Debug()
+	m.addRoutes(a)
+	a.Start()
+
+	err := mbot.Start()
+	if ferr := m.finalizeAdaptors(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// adaptor returns the adaptor of the I2C bus, platforms on the same bus share it
+func (m *Master) adaptor(bus int) *raspi.Adaptor {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if a, found := m.adaptors[bus]; found {
+		return a
+	}
+	if m.adaptors == nil {
+		m.adaptors = map[int]*raspi.Adaptor{}
+	}
+	a := raspi.NewAdaptor()
+	m.adaptors[bus] = a
+	return a
+}
+
+// finalizeAdaptors closes the shared adaptors once the robots using them are stopped
+func (m *Master) finalizeAdaptors() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var err error
+	for _, a := range m.adaptors {
+		if ferr := a.Finalize(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// checkAddress verifies no other platform uses the same GrovePi bus and address
+func (m *Master) checkAddress(platform *GrovePi, bus, address int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, p := range m.platforms {
+		if p == platform {
+			continue
+		}
+		if robot, b, a := p.state(); robot != nil && b == bus && a == address {
+			return ErrorAddressInUse
+		}
+	}
+	return nil
+}
+
+// platform returns the initialized platform with the given name
+func (m *Master) platform(name string) (*GrovePi, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if p := m.find(name); p != nil {
+		if robot, _, _ := p.state(); robot != nil {
+			return p, nil
+		}
+	}
+	return nil, ErrorPlatformNotFound
+}
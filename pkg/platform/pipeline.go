@@ -0,0 +1,151 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"gobot.io/x/gobot"
+)
+
+// Calibration types of the calibration property, any other name selects a built-in preset
+const (
+	LinearCalibration     = "linear"
+	PolynomialCalibration = "polynomial"
+	TableCalibration      = "table"
+	ThermistorCalibration = "thermistor"
+)
+
+// newPipeline creates the processing pipeline of the device, nil when the device config doesn't need one
+func newPipeline(d gobot.Device, cfg *config.DeviceConfig) (*driver.Pipeline, error) {
+	if _, found := cfg.Properties[CalibrationPropertyName]; !found {
+		return nil, nil
+	}
+	if _, ok := d.(gobot.Eventer); !ok {
+		return nil, fmt.Errorf("%w: %s doesn't publish values", ErrorInvalidProperty, cfg.Name)
+	}
+
+	calibration, err := calibrationProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return driver.NewPipeline(calibration), nil
+}
+
+// attachPipelines attaches the platform pipelines to their devices and detaches the old ones which aren't used anymore,
+// the caller must hold the mutex
+func (p *GrovePi) attachPipelines(old map[string]*driver.Pipeline) {
+	for name, pipeline := range p.pipelines {
+		if source, ok := p.devicesByName[name].(gobot.Eventer); ok {
+			pipeline.Attach(source)
+		}
+	}
+	for name, pipeline := range old {
+		if p.pipelines[name] != pipeline {
+			pipeline.Detach()
+		}
+	}
+}
+
+// calibrationProperty parses the calibration which is either a preset name or a mapping, e.g.
+//   calibration: volts
+//   calibration: {type: linear, scale: 0.1, offset: -5, unit: "°C"}
+//   calibration: {type: polynomial, coefficients: [0.5, 0.01, 0.0001], unit: ppm}
+//   calibration: {type: table, points: [[0, 0], [512, 40], [1023, 100]], unit: "%"}
+//   calibration: {type: thermistor, beta: 4275}
+func calibrationProperty(cfg *config.DeviceConfig) (*driver.Calibration, error) {
+	c, err := parseCalibration(cfg.Properties[CalibrationPropertyName])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, CalibrationPropertyName, cfg.Name, err)
+	}
+	return c, nil
+}
+
+// parseCalibration parses calibration decoded from JSON or YAML, see calibrationProperty
+func parseCalibration(v interface{}) (*driver.Calibration, error) {
+	if name, ok := v.(string); ok {
+		if c, found := driver.PresetCalibration(name); found {
+			return c, nil
+		}
+		return nil, fmt.Errorf("unknown preset %q", name)
+	}
+
+	m, ok := stringMap(v)
+	if !ok {
+		return nil, fmt.Errorf("%T is neither preset name nor mapping", v)
+	}
+	unit, _ := m["unit"].(string)
+
+	switch m["type"] {
+	case LinearCalibration:
+		scale, ok := number(valueOr(m["scale"], 1.0))
+		offset, ok2 := number(valueOr(m["offset"], 0.0))
+		if !ok || !ok2 {
+			return nil, errors.New("scale and offset should be numbers")
+		}
+		return driver.NewLinearCalibration(scale, offset, unit), nil
+	case PolynomialCalibration:
+		coefficients, ok := numbers(m["coefficients"])
+		if !ok {
+			return nil, errors.New("coefficients should be a list of numbers")
+		}
+		return driver.NewPolynomialCalibration(coefficients, unit)
+	case TableCalibration:
+		rows, ok := m["points"].([]interface{})
+		if !ok {
+			return nil, errors.New("points should be a list of [raw, value] pairs")
+		}
+		points := make([]driver.CalibrationPoint, 0, len(rows))
+		for _, row := range rows {
+			pair, ok := numbers(row)
+			if !ok || len(pair) != 2 {
+				return nil, errors.New("points should be a list of [raw, value] pairs")
+			}
+			points = append(points, driver.CalibrationPoint{Raw: pair[0], Value: pair[1]})
+		}
+		return driver.NewTableCalibration(points, unit)
+	case ThermistorCalibration:
+		beta, ok := number(valueOr(m["beta"], driver.DefaultThermistorBeta))
+		if !ok {
+			return nil, errors.New("beta should be a number")
+		}
+		return driver.NewThermistorCalibration(beta)
+	default:
+		return nil, fmt.Errorf("unknown type %v", m["type"])
+	}
+}
+
+func valueOr(v, def interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	return v
+}
+
+// number returns numeric value decoded from JSON or YAML
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// numbers returns list of numeric values decoded from JSON or YAML
+func numbers(v interface{}) ([]float64, bool) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	values := make([]float64, 0, len(list))
+	for _, item := range list {
+		n, ok := number(item)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, n)
+	}
+	return values, true
+}
@@ -2,25 +2,40 @@ package platform
 
 import (
 	"errors"
+	"fmt"
 	"gobot-grovepi-platform/pkg/config"
 	driver "gobot-grovepi-platform/pkg/gobot-driver"
 	"gobot.io/x/gobot"
-	"gobot.io/x/gobot/api"
 	"gobot.io/x/gobot/drivers/aio"
 	"gobot.io/x/gobot/drivers/gpio"
 	"gobot.io/x/gobot/drivers/i2c"
 	"gobot.io/x/gobot/platforms/raspi"
+	"math"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
+// GrovePi is a single GrovePi board run as a gobot robot named after the platform
 type GrovePi struct {
+	mutex         sync.Mutex
+	name          string
+	master        *Master
 	adaptor       *raspi.Adaptor
+	grovepi       *driver.GrovePiDriver
+	// stateMutex guards robot, bus and address for the master, which reads them without holding mutex
+	stateMutex    sync.Mutex
 	robot         *gobot.Robot
+	bus           int
+	address       int
+	conf          *config.GrovePiConfig
 	devicesByPin  map[string]gobot.Device
 	devicesByName map[string]gobot.Device
+	deviceConfigs map[string]*config.DeviceConfig
+	pipelines     map[string]*driver.Pipeline
+	monitors      map[string]*driver.Monitor
+	gestures      map[string]*driver.Gestures
 	work          func()
 }
 
@@ -34,16 +49,58 @@ const (
 	GrovePiRGBLCDPanelDriverName      = "GroveLcdDriver"
 	GrovePiDHTSensorDriverName        = "GroveTemperatureAndHumidityDriver"
 	GrovePiUltrasonicRangerDriverName = "GroveUltrasonicRangerDriver"
+	GrovePi4DigitDisplayDriverName    = "Grove4DigitDisplayDriver"
+	GrovePiLedBarDriverName           = "GroveLedBarDriver"
+	GrovePiChainableRGBDriverName     = "GroveChainableRGBDriver"
+	GrovePiIRReceiverDriverName       = "GroveIRReceiverDriver"
+	GrovePiEncoderDriverName          = "GroveEncoderDriver"
+	GrovePiDustSensorDriverName       = "GroveDustSensorDriver"
+	GrovePiFlowSensorDriverName       = "GroveFlowSensorDriver"
+	GrovePiPIRMotionDriverName        = "GrovePIRMotionDriver"
+	GrovePiServoDriverName            = "GroveServoDriver"
+	GrovePiRelayDriverName            = "GroveRelayDriver"
+	GrovePiMOSFETDriverName           = "GroveMOSFETDriver"
+	GrovePiAnalogSensorDriverName     = "GroveAnalogSensorDriver"
 
 	SamplingIntervalPropertyName = "samplingInterval"
+	BrightnessPropertyName       = "brightness"
+	ClockPropertyName            = "clock"
+	ReversedPropertyName         = "reversed"
+	SourcePropertyName           = "source"
+	SourceMinPropertyName        = "sourceMin"
+	SourceMaxPropertyName        = "sourceMax"
+	CountPropertyName            = "count"
+	KeysPropertyName             = "keys"
+	MinPropertyName              = "min"
+	MaxPropertyName              = "max"
+	WindowPropertyName           = "window"
+	KFactorPropertyName          = "kFactor"
+	ModePropertyName             = "mode"
+	CenterPropertyName           = "center"
+	SpeedPropertyName            = "speed"
+	DefaultPropertyName          = "default"
+	FailSafePropertyName         = "failSafe"
+	MinOnPropertyName            = "minOn"
+	MinOffPropertyName           = "minOff"
+	MaxOnPropertyName            = "maxOn"
+	InterlockPropertyName        = "interlock"
+	RetriggerPropertyName        = "retrigger"
+	CalibrationPropertyName      = "calibration"
+	ProfilePropertyName          = "profile"
+	FiltersPropertyName          = "filters"
+	ThresholdsPropertyName       = "thresholds"
+	TypePropertyName             = "type"
+	UnitPropertyName             = "unit"
+	PublishPropertyName          = "publish"
+	TemperaturePropertyName      = "temperature"
+	GesturesPropertyName         = "gestures"
+	MelodiesPropertyName         = "melodies"
+	ClearPropertyName            = "clear"
 
 	RobotDefaultName = "gobot-grovepi-platform"
 )
 
 var (
-	platformOnce     sync.Once
-	platformInstance *GrovePi
-
 	deviceFactories = map[string]func(*driver.GrovePiDriver, *config.DeviceConfig, *raspi.Adaptor) (gobot.Device, error){
 		GrovePiLEDDriverName:              newLed,
 		GrovePiRotarySensorDriverName:     newRotary,
@@ -54,6 +111,26 @@ var (
 		GrovePiRGBLCDPanelDriverName:      newLcdPanel,
 		GrovePiDHTSensorDriverName:        newDHT,
 		GrovePiUltrasonicRangerDriverName: newUltrasonicRanger,
+		GrovePi4DigitDisplayDriverName:    new4DigitDisplay,
+		GrovePiLedBarDriverName:           newLedBar,
+		GrovePiChainableRGBDriverName:     newChainableRGB,
+		GrovePiIRReceiverDriverName:       newIRReceiver,
+		GrovePiEncoderDriverName:          newEncoder,
+		GrovePiDustSensorDriverName:       newDustSensor,
+		GrovePiFlowSensorDriverName:       newFlowSensor,
+		GrovePiPIRMotionDriverName:        newPIRMotion,
+		GrovePiServoDriverName:            newServo,
+		GrovePiRelayDriverName:            newRelay,
+		GrovePiMOSFETDriverName:           newMOSFET,
+		GrovePiAnalogSensorDriverName:     newAnalogSensor,
+	}
+
+	deviceBinders = map[string]func(gobot.Device, *config.DeviceConfig, map[string]gobot.Device) error{
+		GrovePiLedBarDriverName: bindLedBar,
+		GrovePiRelayDriverName:  bindInterlock,
+		GrovePiMOSFETDriverName: bindInterlock,
+
+		GrovePiUltrasonicRangerDriverName: bindTemperatureSource,
 	}
 
 	ErrorAlreadyInitialized = errors.New("already initialized")
@@ -62,27 +139,60 @@ var (
 	ErrorPinAlreadyInUse    = errors.New("pin already in use")
 	ErrorInvalidI2CAddress  = errors.New("invalid I2C address")
 	ErrorNameAlreadyInUse   = errors.New("name already in use")
+	ErrorUnknownCommand     = errors.New("unknown GrovePi command")
+	ErrorAddressInUse       = errors.New("I2C address already in use by another platform")
+	ErrorPlatformNotFound   = errors.New("platform not found")
+	ErrorDeviceNotFound     = errors.New("device not found")
+	ErrorDeviceInUse        = errors.New("device used by another device")
+	ErrorReloadNotSupported = errors.New("bus or address can't be changed without restart")
+	ErrorNoConfigFile       = errors.New("platforms weren't initialized from a config file")
+	ErrorDeviceTypeMismatch = errors.New("device driver type mismatch")
+	ErrorInvalidProperty    = errors.New("invalid device property")
+	ErrorInterruptInUse     = errors.New("firmware interrupt pin D2 already in use")
 )
 
+// GetPlatform returns the default platform
 func GetPlatform() *GrovePi {
-	platformOnce.Do(func() {
-		platformInstance = &GrovePi{
-			adaptor:       raspi.NewAdaptor(),
-			devicesByPin:  map[string]gobot.Device{},
-			devicesByName: map[string]gobot.Device{},
-			work:          func() {},
-		}
-	})
-	return platformInstance
+	return GetMaster().platformOrAdd(RobotDefaultName)
+}
+
+func newGrovePi(name string, m *Master) *GrovePi {
+	return &GrovePi{
+		name:          name,
+		master:        m,
+		devicesByPin:  map[string]gobot.Device{},
+		devicesByName: map[string]gobot.Device{},
+		deviceConfigs: map[string]*config.DeviceConfig{},
+		pipelines:     map[string]*driver.Pipeline{},
+		monitors:      map[string]*driver.Monitor{},
+		gestures:      map[string]*driver.Gestures{},
+		work:          func() {},
+	}
 }
 
+// Name returns the platform name which is also the name of its robot
+func (p *GrovePi) Name() string { return p.name }
+
 func (p *GrovePi) Init(conf *config.GrovePiConfig, w ...func()) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 
 	if p.robot != nil {
 		return ErrorAlreadyInitialized
 	}
 
-	gp := driver.NewGrovePiDriver(p.adaptor, i2c.WithBus(conf.Bus), i2c.WithAddress(conf.Address))
+	address := grovePiAddress(conf)
+	if err := p.master.checkAddress(p, conf.Bus, address); err != nil {
+		return err
+	}
+
+	options, err := grovePiOptions(conf)
+	if err != nil {
+		return err
+	}
+
+	p.adaptor = p.master.adaptor(conf.Bus)
+	gp := driver.NewGrovePiDriver(p.adaptor, options...)
 	devices, err := p.createDevices(gp, conf.Devices...)
 
 	ds := make([]gobot.Device, 0)
@@ -93,34 +203,45 @@ func (p *GrovePi) Init(conf *config.GrovePiConfig, w ...func()) error {
 		return err
 	}
 
+	if err := bindDevices(p.devicesByName, p.deviceConfigs); err != nil {
+		return err
+	}
+
 	if w != nil && len(w) > 0 {
 		p.work = w[0]
 	}
 
-	p.robot = gobot.NewRobot(RobotDefaultName,
-		[]gobot.Connection{p.adaptor, gp},
+	p.grovepi = gp
+	p.conf = conf
+	p.attachPipelines(nil)
+	p.attachMonitors(nil)
+	p.attachGestures(nil)
+	// the shared adaptor is finalized by the master after all robots are stopped
+	robot := gobot.NewRobot(p.name,
+		[]gobot.Connection{gp},
 		ds,
 		p.work)
+
+	p.stateMutex.Lock()
+	p.robot, p.bus, p.address = robot, conf.Bus, address
+	p.stateMutex.Unlock()
 	return nil
 }
 
-func (p *GrovePi) Run() error {
-	if p.robot == nil {
-		return ErrorNotInitialized
-	}
-
-	mbot := gobot.NewMaster()
-	mbot.AddRobot(p.robot)
+// state returns the robot, bus and address of the platform, the robot is nil until the platform is initialized
+func (p *GrovePi) state() (robot *gobot.Robot, bus, address int) {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
 
-	a := api.NewAPI(mbot)
-	a.Debug()
-	a.Start()
+	return p.robot, p.bus, p.address
+}
 
-	err := mbot.Start()
-	if err != nil {
-		return err
+// Run starts all initialized platforms, see Master.Run
+func (p *GrovePi) Run() error {
+	if robot, _, _ := p.state(); robot == nil {
+		return ErrorNotInitialized
 	}
-	return nil
+	return p.master.Run()
 }
 
 func (p *GrovePi) createDevices(gp *driver.GrovePiDriver, conf ...*config.DeviceConfig) (devices []gobot.Device, err error) {
@@ -136,58 +257,306 @@ func (p *GrovePi) createDevices(gp *driver.GrovePiDriver, conf ...*config.Device
 		if _, inUse := p.devicesByName[cfg.Name]; inUse {
 			return nil, ErrorNameAlreadyInUse
 		}
+		if usesInterrupt(cfg) {
+			if other, inUse := p.interruptUser(); inUse {
+				return nil, fmt.Errorf("%w: %s and %s", ErrorInterruptInUse, other, cfg.Name)
+			}
+		}
 		createDevice, found := deviceFactories[cfg.Driver]
 		if !found {
 			return nil, ErrorDriverNotSupported
 		}
+		for property, drivers := range driverProperties {
+			if _, found := cfg.Properties[property]; found && !drivers[cfg.Driver] {
+				return nil, fmt.Errorf("%w: %s of %s isn't supported by %s", ErrorInvalidProperty, property, cfg.Name, cfg.Driver)
+			}
+		}
 		d, err := createDevice(gp, cfg, p.adaptor)
 		if err != nil {
 			return nil, err
 		}
 		if d != nil {
+			pipeline, err := newPipeline(d, cfg)
+			if err != nil {
+				return nil, err
+			}
+			monitor, err := newMonitor(d, cfg)
+			if err != nil {
+				return nil, err
+			}
+			gestures, err := newGestures(d, cfg)
+			if err != nil {
+				return nil, err
+			}
 			d.SetName(cfg.Name)
 			p.devicesByName[cfg.Name] = d
 			p.devicesByPin[cfg.Pin] = d
+			p.deviceConfigs[cfg.Name] = cfg
+			if pipeline != nil {
+				p.pipelines[cfg.Name] = pipeline
+			}
+			if monitor != nil {
+				p.monitors[cfg.Name] = monitor
+			}
+			if gestures != nil {
+				p.gestures[cfg.Name] = gestures
+			}
 			devices = append(devices, d)
 		}
 	}
 	return devices, nil
 }
 
+// usesInterrupt returns true when the device needs the firmware interrupt handler,
+// the firmware has a single one which serves the dust, flow and encoder sensors and latched inputs
+func usesInterrupt(cfg *config.DeviceConfig) bool {
+	switch cfg.Driver {
+	case GrovePiDustSensorDriverName, GrovePiFlowSensorDriverName, GrovePiEncoderDriverName:
+		return true
+	case GrovePiButtonDriverName, GrovePiPIRMotionDriverName:
+		mode, _ := stringProperty(cfg, ModePropertyName, driver.InputModePolling)
+		return mode == driver.InputModeLatched
+	}
+	return false
+}
+
+// interruptUser returns the name of the platform device which uses the firmware interrupt handler
+func (p *GrovePi) interruptUser() (string, bool) {
+	for name, cfg := range p.deviceConfigs {
+		if usesInterrupt(cfg) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// bindDevices connects devices to the platform devices they depend on
+func bindDevices(devices map[string]gobot.Device, configs map[string]*config.DeviceConfig) error {
+	for name, cfg := range configs {
+		if bind, found := deviceBinders[cfg.Driver]; found {
+			if err := bind(devices[name], cfg, devices); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func grovePiOptions(conf *config.GrovePiConfig) ([]func(i2c.Config), error) {
+	options := []func(i2c.Config){i2c.WithBus(conf.Bus), i2c.WithAddress(grovePiAddress(conf))}
+
+	policy, err := retryPolicy(driver.DefaultRetryPolicy, conf.Retry)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, driver.WithRetryPolicy(0, policy))
+
+	for name, rc := range conf.Commands {
+		cmd, found := driver.CommandByName(name)
+		if !found {
+			return nil, ErrorUnknownCommand
+		}
+		cmdPolicy, err := retryPolicy(policy, rc)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, driver.WithRetryPolicy(cmd, cmdPolicy))
+	}
+
+	resetAfter := conf.ResetAfter
+	if resetAfter == 0 {
+		resetAfter = driver.DefaultResetThreshold
+	}
+	options = append(options, driver.WithResetThreshold(resetAfter))
+	return options, nil
+}
+
+// grovePiAddress returns the I2C address of the GrovePi, the firmware default when it isn't set
+func grovePiAddress(conf *config.GrovePiConfig) int {
+	if conf.Address == 0 {
+		return driver.DefaultAddress
+	}
+	return conf.Address
+}
+
+func retryPolicy(base driver.RetryPolicy, rc *config.RetryConfig) (driver.RetryPolicy, error) {
+	if rc == nil {
+		return base, nil
+	}
+	p := base
+	if rc.Attempts > 0 {
+		p.Attempts = rc.Attempts
+	}
+	durations := []struct {
+		value string
+		dst   *time.Duration
+	}{
+		{rc.Backoff, &p.Backoff},
+		{rc.MaxBackoff, &p.MaxBackoff},
+		{rc.Timeout, &p.Timeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return base, err
+		}
+		*d.dst = duration
+	}
+	return p, nil
+}
+
 //-------------------------------------------------------------------------------------------------------------
 func newButton(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
-		if err != nil {
-			return nil, err
-		}
-		return gpio.NewGroveButtonDriver(gp, cfg.Pin, duration), nil
+	mode, err := stringProperty(cfg, ModePropertyName, driver.InputModePolling)
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case driver.InputModePolling:
+	case driver.InputModeLatched:
+		return newLatchedButton(gp, cfg)
+	default:
+		return nil, fmt.Errorf("%w: %s of %s should be %s or %s", ErrorInvalidProperty, ModePropertyName, cfg.Name,
+			driver.InputModePolling, driver.InputModeLatched)
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		return gpio.NewGroveButtonDriver(gp, cfg.Pin, interval), nil
 	}
 	return gpio.NewGroveButtonDriver(gp, cfg.Pin), nil
 }
 
+func newLatchedButton(gp *driver.GrovePiDriver, cfg *config.DeviceConfig) (gobot.Device, error) {
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		return driver.NewGroveLatchedButtonDriver(gp, cfg.Pin, interval), nil
+	}
+	return driver.NewGroveLatchedButtonDriver(gp, cfg.Pin), nil
+}
+
 func newBuzzer(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	return driver.NewGroveBuzzerDriver(gp, cfg.Pin), nil
+	d := driver.NewGroveBuzzerDriver(gp, cfg.Pin)
+
+	// melodies are RTTTL ringtones or note sequences by name
+	m, err := mapProperty(cfg, MelodiesPropertyName)
+	if err != nil {
+		return nil, err
+	}
+	melodies := make(map[string]*driver.Melody, len(m))
+	for name, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: melody %s of %s is %T, not a string", ErrorInvalidProperty, name, cfg.Name, v)
+		}
+		melody, err := driver.ParseMelody(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: melody %s of %s: %v", ErrorInvalidProperty, name, cfg.Name, err)
+		}
+		if melody.Name == "" {
+			melody.Name = name
+		}
+		melodies[name] = melody
+	}
+	d.SetMelodies(melodies)
+	return d, nil
 }
 
 func newDHT(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
-		if err != nil {
-			return nil, err
+	var d *driver.GroveTemperatureAndHumidityDriver
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		d = driver.NewGroveTemperatureAndHumidityDriver(gp, cfg.Pin, interval)
+	} else {
+		d = driver.NewGroveTemperatureAndHumidityDriver(gp, cfg.Pin)
+	}
+
+	temperatureFilters, humidityFilters, err := dhtFiltersProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.SetFilters(temperatureFilters, humidityFilters)
+	if err := setPublishPolicy(d, cfg); err != nil {
+		return nil, err
+	}
+
+	module, err := dhtModuleProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetModule(module); err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, TypePropertyName, cfg.Name, err)
+	}
+
+	unit, err := stringProperty(cfg, UnitPropertyName, driver.Celsius)
+	if err != nil {
+		return nil, err
+	}
+	if name, found := temperatureUnits[strings.ToLower(unit)]; found {
+		unit = name
+	}
+	if err := d.SetUnit(unit); err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, UnitPropertyName, cfg.Name, err)
+	}
+	return d, nil
+}
+
+// temperatureUnits are the names of the temperature units of the unit property
+var temperatureUnits = map[string]string{
+	"c":          driver.Celsius,
+	"celsius":    driver.Celsius,
+	"f":          driver.Fahrenheit,
+	"fahrenheit": driver.Fahrenheit,
+	"k":          driver.Kelvin,
+	"kelvin":     driver.Kelvin,
+}
+
+// dhtModules are the DHT module types of the type property
+var dhtModules = map[string]byte{
+	"DHT11":  driver.DHT11,
+	"DHT22":  driver.DHT22,
+	"AM2302": driver.DHT22,
+	"DHT21":  driver.DHT21,
+	"AM2301": driver.AM2301,
+}
+
+// dhtModuleProperty returns the DHT module type given by name, e.g. DHT22, or by the firmware number
+func dhtModuleProperty(cfg *config.DeviceConfig) (byte, error) {
+	if s, ok := cfg.Properties[TypePropertyName].(string); ok {
+		module, found := dhtModules[strings.ToUpper(s)]
+		if !found {
+			return 0, fmt.Errorf("%w: unknown %s %q of %s", ErrorInvalidProperty, TypePropertyName, s, cfg.Name)
 		}
-		return driver.NewGroveTemperatureAndHumidityDriver(gp, cfg.Pin, duration), nil
+		return module, nil
 	}
-	return driver.NewGroveTemperatureAndHumidityDriver(gp, cfg.Pin), nil
+	module, err := intProperty(cfg, TypePropertyName, driver.DHT11)
+	if err != nil {
+		return 0, err
+	}
+	if module < 0 || module > math.MaxUint8 {
+		return 0, fmt.Errorf("%w: %s %d of %s", ErrorInvalidProperty, TypePropertyName, module, cfg.Name)
+	}
+	return byte(module), nil
 }
 
 func newLed(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
@@ -201,56 +570,565 @@ func newLightSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
+	reader, err := analogReader(gp, cfg)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		return aio.NewGroveLightSensorDriver(reader, cfg.Pin, interval), nil
+	}
+	return aio.NewGroveLightSensorDriver(reader, cfg.Pin), nil
+}
+
+func newRotary(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	reader, err := analogReader(gp, cfg)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		return aio.NewGroveRotaryDriver(reader, cfg.Pin, interval), nil
+	}
+	return aio.NewGroveRotaryDriver(reader, cfg.Pin), nil
+}
+
+func newSoundSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	reader, err := analogReader(gp, cfg)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		return aio.NewGroveSoundSensorDriver(reader, cfg.Pin, interval), nil
+	}
+	return aio.NewGroveSoundSensorDriver(reader, cfg.Pin), nil
+}
+
+func newUltrasonicRanger(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	var d *driver.GroveUltrasonicRangerDriver
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		d = driver.NewGroveUltrasonicRangerDriver(gp, cfg.Pin, interval)
+	} else {
+		d = driver.NewGroveUltrasonicRangerDriver(gp, cfg.Pin)
+	}
+
+	filters, err := filtersProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.SetFilters(filters)
+	if err := setPublishPolicy(d, cfg); err != nil {
+		return nil, err
+	}
+
+	unit, err := stringProperty(cfg, UnitPropertyName, driver.Centimeters)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetUnit(unit); err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, UnitPropertyName, cfg.Name, err)
+	}
+	_, hasMin := cfg.Properties[MinPropertyName]
+	_, hasMax := cfg.Properties[MaxPropertyName]
+	if hasMin || hasMax {
+		min, err := floatProperty(cfg, MinPropertyName, 0)
+		if err != nil {
+			return nil, err
+		}
+		max, err := floatProperty(cfg, MaxPropertyName, math.MaxFloat64)
 		if err != nil {
 			return nil, err
 		}
-		return aio.NewGroveLightSensorDriver(gp, cfg.Pin, duration), nil
+		if err := d.SetRange(min, max); err != nil {
+			return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, MaxPropertyName, cfg.Name, err)
+		}
 	}
-	return aio.NewGroveLightSensorDriver(gp, cfg.Pin), nil
+	return d, nil
 }
 
-func newRotary(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+func new4DigitDisplay(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	brightness, err := intProperty(cfg, BrightnessPropertyName, driver.DefaultFourDigitBrightness)
+	if err != nil {
+		return nil, err
+	}
+	if brightness < 0 || brightness > 7 {
+		return nil, fmt.Errorf("%w: %s of %s should be from 0 to 7", ErrorInvalidProperty, BrightnessPropertyName, cfg.Name)
+	}
+	clock, err := boolProperty(cfg, ClockPropertyName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d := driver.NewGrove4DigitDisplayDriver(gp, cfg.Pin)
+	d.SetBrightness(byte(brightness))
+	d.SetClockMode(clock)
+	return d, nil
+}
+
+func newLedBar(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	reversed, err := boolProperty(cfg, ReversedPropertyName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	d := driver.NewGroveLedBarDriver(gp, cfg.Pin)
+	d.SetReversed(reversed)
+	return d, nil
+}
+
+func bindLedBar(d gobot.Device, cfg *config.DeviceConfig, devices map[string]gobot.Device) error {
+	bar, ok := d.(*driver.GroveLedBarDriver)
+	if !ok {
+		return ErrorDeviceTypeMismatch
+	}
+	source, err := stringProperty(cfg, SourcePropertyName, "")
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		bar.Unbind()
+		return nil
+	}
+
+	src, found := devices[source].(gobot.Eventer)
+	if !found {
+		return fmt.Errorf("%w: %s of %s should name a device publishing events", ErrorInvalidProperty, SourcePropertyName, cfg.Name)
+	}
+	min, err := intProperty(cfg, SourceMinPropertyName, 0)
+	if err != nil {
+		return err
+	}
+	max, err := intProperty(cfg, SourceMaxPropertyName, 1023)
+	if err != nil {
+		return err
+	}
+	return bar.Bind(src, min, max)
+}
+
+func newChainableRGB(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
+	count, err := intProperty(cfg, CountPropertyName, 1)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 || count > 255 {
+		return nil, fmt.Errorf("%w: %s of %s should be from 1 to 255", ErrorInvalidProperty, CountPropertyName, cfg.Name)
+	}
+	return driver.NewGroveChainableRGBDriver(gp, cfg.Pin, count), nil
+}
+
+func newIRReceiver(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	keys, err := irKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *driver.GroveIRReceiverDriver
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		d = driver.NewGroveIRReceiverDriver(gp, cfg.Pin, interval)
+	} else {
+		d = driver.NewGroveIRReceiverDriver(gp, cfg.Pin)
+	}
+	d.SetKeys(keys)
+	return d, nil
+}
+
+// irKeys parses the remote key map, keys are NEC codes or commands, e.g. "0x00FF45BA: power" or "0x45: power"
+func irKeys(cfg *config.DeviceConfig) (map[uint32]string, error) {
+	m, err := mapProperty(cfg, KeysPropertyName)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[uint32]string, len(m))
+	for code, key := range m {
+		c, err := strconv.ParseUint(code, 0, 32)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, KeysPropertyName, cfg.Name, err)
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s of %s: key of %s should be a name", ErrorInvalidProperty, KeysPropertyName, cfg.Name, code)
 		}
-		return aio.NewGroveRotaryDriver(gp, cfg.Pin, duration), nil
+		keys[uint32(c)] = name
 	}
-	return aio.NewGroveRotaryDriver(gp, cfg.Pin), nil
+	return keys, nil
 }
 
-func newSoundSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+func newEncoder(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
+
+	var d *driver.GroveEncoderDriver
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		d = driver.NewGroveEncoderDriver(gp, cfg.Pin, interval)
+	} else {
+		d = driver.NewGroveEncoderDriver(gp, cfg.Pin)
+	}
+
+	_, hasMin := cfg.Properties[MinPropertyName]
+	_, hasMax := cfg.Properties[MaxPropertyName]
+	if hasMin || hasMax {
+		min, err := intProperty(cfg, MinPropertyName, 0)
 		if err != nil {
 			return nil, err
 		}
-		return aio.NewGroveSoundSensorDriver(gp, cfg.Pin, duration), nil
+		max, err := intProperty(cfg, MaxPropertyName, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.SetRange(min, max); err != nil {
+			return nil, err
+		}
 	}
-	return aio.NewGroveSoundSensorDriver(gp, cfg.Pin), nil
+	return d, nil
 }
 
-func newUltrasonicRanger(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+func newDustSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *driver.GroveDustSensorDriver
+	if interval > 0 {
+		d = driver.NewGroveDustSensorDriver(gp, interval)
+	} else {
+		d = driver.NewGroveDustSensorDriver(gp)
+	}
+
+	if window, err := durationProperty(cfg, WindowPropertyName, 0); err != nil {
+		return nil, err
+	} else if window != 0 {
+		if err := d.SetWindow(window); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func newFlowSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *driver.GroveFlowSensorDriver
+	if interval > 0 {
+		d = driver.NewGroveFlowSensorDriver(gp, interval)
+	} else {
+		d = driver.NewGroveFlowSensorDriver(gp)
+	}
+
+	if window, err := durationProperty(cfg, WindowPropertyName, 0); err != nil {
+		return nil, err
+	} else if window != 0 {
+		if err := d.SetWindow(window); err != nil {
+			return nil, err
+		}
+	}
+	kFactor, err := floatProperty(cfg, KFactorPropertyName, driver.DefaultFlowKFactor)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetKFactor(kFactor); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func newPIRMotion(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *driver.GrovePIRMotionDriver
+	if interval > 0 {
+		d = driver.NewGrovePIRMotionDriver(gp, cfg.Pin, interval)
+	} else {
+		d = driver.NewGrovePIRMotionDriver(gp, cfg.Pin)
+	}
+
+	mode, err := stringProperty(cfg, ModePropertyName, driver.InputModePolling)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetMode(mode); err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, ModePropertyName, cfg.Name, err)
+	}
+
+	retrigger, err := durationProperty(cfg, RetriggerPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	clear, err := durationProperty(cfg, ClearPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetTimeouts(retrigger, clear); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+	window, err := durationProperty(cfg, WindowPropertyName, driver.DefaultPIRWindow)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetWindow(window); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+	return d, nil
+}
+
+func newServo(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
 	if gp == nil {
 		return nil, ErrorNotInitialized
 	}
-	if durationStr, found := cfg.Properties[SamplingIntervalPropertyName]; found {
-		duration, err := time.ParseDuration(durationStr.(string))
+	d := driver.NewGroveServoDriver(gp, cfg.Pin)
+
+	min, err := floatProperty(cfg, MinPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	max, err := floatProperty(cfg, MaxPropertyName, 180)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetLimits(min, max); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+
+	center, err := floatProperty(cfg, CenterPropertyName, 90)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetCenter(center); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+
+	speed, err := floatProperty(cfg, SpeedPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.SetSpeed(speed); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+	return d, nil
+}
+
+func newRelay(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	d := driver.NewGroveRelayDriver(gp, cfg.Pin)
+
+	initial, err := boolProperty(cfg, DefaultPropertyName, false)
+	if err != nil {
+		return nil, err
+	}
+	failSafe, err := boolProperty(cfg, FailSafePropertyName, false)
+	if err != nil {
+		return nil, err
+	}
+	d.SetStates(initial, failSafe)
+
+	if err := actuatorLimits(d, cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func newMOSFET(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	d := driver.NewGroveMOSFETDriver(gp, cfg.Pin)
+
+	var levels [2]byte
+	for i, name := range []string{DefaultPropertyName, FailSafePropertyName} {
+		level, err := intProperty(cfg, name, 0)
 		if err != nil {
 			return nil, err
 		}
-		return aio.NewGroveLightSensorDriver(gp, cfg.Pin, duration), nil
+		if level < 0 || level > 255 {
+			return nil, fmt.Errorf("%w: %s of %s should be from 0 to 255", ErrorInvalidProperty, name, cfg.Name)
+		}
+		levels[i] = byte(level)
+	}
+	d.SetStates(levels[0], levels[1])
+
+	if err := actuatorLimits(d, cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// limitedActuator is implemented by the relay and MOSFET drivers
+type limitedActuator interface {
+	SetTimes(minOn, minOff, maxOn time.Duration) error
+	SetInterlockGroup(group string)
+}
+
+// actuatorLimits applies the switching times and the interlock group name of the actuator
+func actuatorLimits(d limitedActuator, cfg *config.DeviceConfig) error {
+	var times [3]time.Duration
+	for i, name := range []string{MinOnPropertyName, MinOffPropertyName, MaxOnPropertyName} {
+		t, err := durationProperty(cfg, name, 0)
+		if err != nil {
+			return err
+		}
+		times[i] = t
+	}
+	if err := d.SetTimes(times[0], times[1], times[2]); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrorInvalidProperty, cfg.Name, err)
+	}
+
+	group, err := stringProperty(cfg, InterlockPropertyName, "")
+	if err != nil {
+		return err
+	}
+	d.SetInterlockGroup(group)
+	return nil
+}
+
+// interlocked is implemented by the actuators sharing an interlock group
+type interlocked interface {
+	InterlockGroup() string
+	Interlock() *driver.Interlock
+	SetInterlock(i *driver.Interlock) error
+}
+
+// bindInterlock joins the actuator to the interlock group of other devices with the same group name
+func bindInterlock(d gobot.Device, cfg *config.DeviceConfig, devices map[string]gobot.Device) error {
+	a, ok := d.(interlocked)
+	if !ok {
+		return ErrorDeviceTypeMismatch
+	}
+	group := a.InterlockGroup()
+	if group == "" {
+		return a.SetInterlock(nil)
+	}
+
+	for _, other := range devices {
+		if o, ok := other.(interlocked); ok && other != d && o.InterlockGroup() == group && o.Interlock() != nil {
+			return a.SetInterlock(o.Interlock())
+		}
+	}
+	return a.SetInterlock(driver.NewInterlock(group))
+}
+
+// bindTemperatureSource sets the air temperature of the ranger which is either fixed in Celsius degrees
+// or read from the named temperature sensor
+func bindTemperatureSource(d gobot.Device, cfg *config.DeviceConfig, devices map[string]gobot.Device) error {
+	ranger, ok := d.(*driver.GroveUltrasonicRangerDriver)
+	if !ok {
+		return ErrorDeviceTypeMismatch
+	}
+
+	switch v := cfg.Properties[TemperaturePropertyName].(type) {
+	case nil:
+		ranger.SetTemperatureSource(nil)
+	case string:
+		sensor, found := devices[v].(interface{ CelsiusReading() (float64, bool) })
+		if !found {
+			return fmt.Errorf("%w: %s of %s should name a temperature sensor", ErrorInvalidProperty, TemperaturePropertyName, cfg.Name)
+		}
+		ranger.SetTemperatureSource(sensor.CelsiusReading)
+	default:
+		celsius, err := floatProperty(cfg, TemperaturePropertyName, 0)
+		if err != nil {
+			return err
+		}
+		ranger.SetTemperatureSource(func() (float64, bool) { return celsius, true })
+	}
+	return nil
+}
+
+func newAnalogSensor(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, _ *raspi.Adaptor) (gobot.Device, error) {
+	if gp == nil {
+		return nil, ErrorNotInitialized
+	}
+	name, err := stringProperty(cfg, ProfilePropertyName, "")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s of %s is required", ErrorInvalidProperty, ProfilePropertyName, cfg.Name)
+	}
+	if _, found := cfg.Properties[CalibrationPropertyName]; found {
+		return nil, fmt.Errorf("%w: %s of %s is defined by its profile", ErrorInvalidProperty, CalibrationPropertyName, cfg.Name)
+	}
+	profile, err := sensorProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := filtersProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := durationProperty(cfg, SamplingIntervalPropertyName, 0)
+	if err != nil {
+		return nil, err
+	}
+	var d *driver.GroveAnalogSensorDriver
+	if interval > 0 {
+		d = driver.NewGroveAnalogSensorDriver(gp, cfg.Pin, profile, interval)
+	} else {
+		d = driver.NewGroveAnalogSensorDriver(gp, cfg.Pin, profile)
 	}
-	return driver.NewGroveUltrasonicRangerDriver(gp, cfg.Pin), nil
+	d.SetFilters(filters)
+	return d, nil
 }
 
 func newLcdPanel(gp *driver.GrovePiDriver, cfg *config.DeviceConfig, raspi *raspi.Adaptor) (gobot.Device, error) {
@@ -0,0 +1,638 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestPlatform(t *testing.T, devices ...*config.DeviceConfig) *GrovePi {
+	p := newGrovePi("test", &Master{})
+	conf := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(4))
+	conf.Devices = devices
+	if err := p.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func newTestDeviceConfig(name, driverName, pin string) *config.DeviceConfig {
+	return config.NewDeviceConfig(
+		config.WithDeviceName(name),
+		config.WithDeviceDriver(driverName),
+		config.WithDevicePin(pin))
+}
+
+func TestGrovePiReload(t *testing.T) {
+	p := newTestPlatform(t,
+		newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"),
+		newTestDeviceConfig("button", GrovePiButtonDriverName, "D2"))
+	redLed := p.devicesByName["redLed"]
+
+	conf := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(4))
+	conf.Devices = []*config.DeviceConfig{
+		newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"),
+		newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D2"),
+	}
+	if err := p.Reload(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.devicesByName["redLed"] != redLed {
+		t.Error("unchanged device should be kept")
+	}
+	if _, found := p.devicesByName["button"]; found {
+		t.Error("removed device should be dropped")
+	}
+	if p.devicesByPin["D2"] != p.devicesByName["greenLed"] {
+		t.Error("added device should take the released pin")
+	}
+	if n := p.robot.Devices().Len(); n != 3 {
+		t.Errorf("expected 3 robot devices, got %d", n)
+	}
+}
+
+func TestGrovePiReloadInvalidConfig(t *testing.T) {
+	p := newTestPlatform(t, newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"))
+	redLed := p.devicesByName["redLed"]
+
+	conf := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(4))
+	conf.Devices = []*config.DeviceConfig{
+		newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D4"),
+		newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D4"),
+	}
+	if err := p.Reload(conf); err != ErrorPinAlreadyInUse {
+		t.Fatalf("expected pin conflict, got %v", err)
+	}
+
+	if p.devicesByName["redLed"] != redLed || p.devicesByPin["D3"] != redLed {
+		t.Error("running devices should be left intact")
+	}
+	if _, found := p.devicesByName["greenLed"]; found {
+		t.Error("invalid config should not be applied")
+	}
+}
+
+func newTestAppConfig(profile string, board1, board2 []*config.DeviceConfig) *config.AppConfig {
+	conf := config.NewAppConfig(
+		config.WithPlatformsConfig(config.NewGrovePiConfig(config.WithGrovePiName("board1"), config.WithGrovePiAddress(4))),
+		config.WithPlatformsConfig(config.NewGrovePiConfig(config.WithGrovePiName("board2"), config.WithGrovePiAddress(5))))
+	conf.Platform = nil
+	conf.Platforms[0].Devices = board1
+	conf.Platforms[1].Devices = board2
+	conf.Profiles = map[string]*config.SensorProfileConfig{profile: {Calibration: "percent"}}
+	return conf
+}
+
+func TestMasterReloadAtomic(t *testing.T) {
+	defer setProfiles(nil)
+	m := &Master{}
+	conf := newTestAppConfig("tank",
+		[]*config.DeviceConfig{newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3")},
+		[]*config.DeviceConfig{newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3")})
+	if err := m.Init(conf); err != nil {
+		t.Fatal(err)
+	}
+	board1, err := m.Platform("board1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board2, err := m.Platform("board2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unchanged := func() {
+		t.Helper()
+		if _, found := board1.devicesByName["button"]; found || len(board1.conf.Devices) != 1 {
+			t.Error("first platform should be rolled back")
+		}
+		if _, found := board2.devicesByName["greenLed"]; found {
+			t.Error("failed platform should be left intact")
+		}
+		if _, found := profiles()["tank"]; !found || m.conf != conf {
+			t.Error("profiles and config should be left intact")
+		}
+	}
+
+	invalid := newTestAppConfig("uv",
+		[]*config.DeviceConfig{
+			newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"),
+			newTestDeviceConfig("button", GrovePiButtonDriverName, "D4")},
+		[]*config.DeviceConfig{
+			newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"),
+			newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D3")})
+	if err := m.Reload(invalid); err != ErrorPinAlreadyInUse {
+		t.Fatalf("expected pin conflict, got %v", err)
+	}
+	unchanged()
+
+	unknown := newTestAppConfig("uv",
+		[]*config.DeviceConfig{newTestDeviceConfig("button", GrovePiButtonDriverName, "D4")}, nil)
+	unknown.Platforms[1].Name = "board3"
+	if err := m.Reload(unknown); err != ErrorPlatformNotFound {
+		t.Fatalf("expected unknown platform, got %v", err)
+	}
+	unchanged()
+
+	valid := newTestAppConfig("uv",
+		[]*config.DeviceConfig{newTestDeviceConfig("button", GrovePiButtonDriverName, "D4")},
+		[]*config.DeviceConfig{newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D2")})
+	if err := m.Reload(valid); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := board1.devicesByName["button"]; !found {
+		t.Error("first platform should be reloaded")
+	}
+	if _, found := board2.devicesByName["greenLed"]; !found {
+		t.Error("second platform should be reloaded")
+	}
+	if _, found := profiles()["uv"]; !found || m.conf != valid {
+		t.Error("profiles and config should be replaced")
+	}
+}
+
+func TestMasterPlatform(t *testing.T) {
+	m := &Master{}
+	if _, err := m.Platform("board1"); !errors.Is(err, ErrorPlatformNotFound) {
+		t.Errorf("expected unknown platform, got %v", err)
+	}
+	if len(m.platforms) != 0 {
+		t.Error("looking up a platform shouldn't add it")
+	}
+
+	added, err := m.AddPlatform("board1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := m.Platform("board1"); err != nil || p != added {
+		t.Errorf("expected the added platform, got %v", err)
+	}
+	if _, err := m.AddPlatform("board1"); !errors.Is(err, ErrorNameAlreadyInUse) {
+		t.Errorf("expected name in use, got %v", err)
+	}
+	if len(m.Platforms()) != 0 {
+		t.Error("platform shouldn't be listed before it's initialized")
+	}
+}
+
+func TestMasterConcurrentInit(t *testing.T) {
+	m := &Master{}
+	var wg sync.WaitGroup
+	for address := 4; address < 8; address++ {
+		p, _ := m.AddPlatform(fmt.Sprintf("board%d", address))
+		conf := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(address))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := p.Init(conf); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			m.Platforms()
+		}()
+	}
+	wg.Wait()
+	if n := len(m.Platforms()); n != 4 {
+		t.Errorf("expected 4 platforms, got %d", n)
+	}
+}
+
+func TestMasterSharedAdaptor(t *testing.T) {
+	m := &Master{}
+	board1, _ := m.AddPlatform("board1")
+	if err := board1.Init(config.NewGrovePiConfig(config.WithGrovePiBus(1))); err != nil {
+		t.Fatal(err)
+	}
+	if board1.address != driver.DefaultAddress {
+		t.Errorf("expected default address, got %d", board1.address)
+	}
+
+	explicit := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(driver.DefaultAddress))
+	board2, _ := m.AddPlatform("board2")
+	if err := board2.Init(explicit); !errors.Is(err, ErrorAddressInUse) {
+		t.Errorf("expected address in use by the default address, got %v", err)
+	}
+
+	board3, _ := m.AddPlatform("board3")
+	if err := board3.Init(config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(5))); err != nil {
+		t.Fatal(err)
+	}
+	board4, _ := m.AddPlatform("board4")
+	if err := board4.Init(config.NewGrovePiConfig(config.WithGrovePiBus(0))); err != nil {
+		t.Fatal(err)
+	}
+	if board1.adaptor != board3.adaptor || board1.adaptor == board4.adaptor {
+		t.Error("platforms on the same bus should share the adaptor")
+	}
+
+	if err := board1.Reload(config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(4))); err != nil {
+		t.Errorf("explicit default address should reload, got %v", err)
+	}
+}
+
+func TestGrovePiAddRemoveDevice(t *testing.T) {
+	p := newTestPlatform(t, newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"))
+
+	if _, err := p.AddDevice(newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D3")); err != ErrorPinAlreadyInUse {
+		t.Errorf("expected pin conflict, got %v", err)
+	}
+	if _, err := p.AddDevice(newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D4")); err != ErrorNameAlreadyInUse {
+		t.Errorf("expected name conflict, got %v", err)
+	}
+
+	d, err := p.AddDevice(newTestDeviceConfig("greenLed", GrovePiLEDDriverName, "D4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := p.Device("greenLed"); found != d {
+		t.Error("added device should be found by name")
+	}
+	if n := len(p.DeviceConfigs()); n != 2 {
+		t.Errorf("expected 2 device configs, got %d", n)
+	}
+
+	if err := p.RemoveDevice("redLed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Device("redLed"); err != ErrorDeviceNotFound {
+		t.Errorf("expected removed device to be gone, got %v", err)
+	}
+	if devices := p.Devices(); len(devices) != 1 || devices[0] != d {
+		t.Errorf("unexpected devices %v", devices)
+	}
+	if n := p.robot.Devices().Len(); n != 2 {
+		t.Errorf("expected 2 robot devices, got %d", n)
+	}
+}
+
+func TestGrovePiRemoveDeviceNotInitialized(t *testing.T) {
+	p := newGrovePi("test", &Master{})
+	p.devicesByName["led"] = driver.NewGroveRelayDriver(nil, "D3")
+	if err := p.RemoveDevice("led"); !errors.Is(err, ErrorNotInitialized) {
+		t.Errorf("expected not initialized, got %v", err)
+	}
+}
+
+func TestGrovePiInterruptConflict(t *testing.T) {
+	button := newTestDeviceConfig("button", GrovePiButtonDriverName, "D3")
+	p := newTestPlatform(t, newTestDeviceConfig("dust", GrovePiDustSensorDriverName, "D2"), button)
+
+	for _, cfg := range []*config.DeviceConfig{
+		newTestDeviceConfig("flow", GrovePiFlowSensorDriverName, "D4"),
+		newTestDeviceConfig("encoder", GrovePiEncoderDriverName, "D5"),
+		{Name: "pir", Driver: GrovePiPIRMotionDriverName, Pin: "D6",
+			Properties: map[string]interface{}{ModePropertyName: driver.InputModeLatched}},
+	} {
+		if _, err := p.AddDevice(cfg); !errors.Is(err, ErrorInterruptInUse) {
+			t.Errorf("%s: expected interrupt in use, got %v", cfg.Name, err)
+		}
+	}
+
+	if err := p.RemoveDevice("dust"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AddDevice(newTestDeviceConfig("encoder", GrovePiEncoderDriverName, "D2")); err != nil {
+		t.Errorf("released interrupt should allow the encoder, got %v", err)
+	}
+}
+
+func TestGrovePiTypedAccessors(t *testing.T) {
+	p := newTestPlatform(t,
+		newTestDeviceConfig("redLed", GrovePiLEDDriverName, "D3"),
+		newTestDeviceConfig("usranger", GrovePiUltrasonicRangerDriverName, "D6"))
+
+	if _, err := p.LED("redLed"); err != nil {
+		t.Error(err)
+	}
+	if _, err := p.UltrasonicRanger("usranger"); err != nil {
+		t.Error(err)
+	}
+	if _, err := p.DHT("redLed"); !errors.Is(err, ErrorDeviceTypeMismatch) {
+		t.Errorf("expected type mismatch, got %v", err)
+	}
+	if _, err := p.LED("blueLed"); !errors.Is(err, ErrorDeviceNotFound) {
+		t.Errorf("expected missing device, got %v", err)
+	}
+}
+
+func TestGrovePiInterlockGroups(t *testing.T) {
+	heater := newTestDeviceConfig("heater", GrovePiRelayDriverName, "D2")
+	heater.Properties = map[string]interface{}{InterlockPropertyName: "hvac"}
+	cooler := newTestDeviceConfig("cooler", GrovePiRelayDriverName, "D3")
+	cooler.Properties = map[string]interface{}{InterlockPropertyName: "hvac"}
+	p := newTestPlatform(t, heater, cooler, newTestDeviceConfig("fan", GrovePiMOSFETDriverName, "D5"))
+
+	h, _ := p.Relay("heater")
+	c, _ := p.Relay("cooler")
+	f, _ := p.MOSFET("fan")
+	if h.Interlock() == nil || h.Interlock() != c.Interlock() {
+		t.Error("relays of the same group should share the interlock")
+	}
+	if f.Interlock() != nil {
+		t.Error("actuator without group should not be interlocked")
+	}
+}
+
+func TestGrovePiCalibrationProperty(t *testing.T) {
+	light := newTestDeviceConfig("light", GrovePiLightSensorDriverName, "A0")
+	light.Properties = map[string]interface{}{CalibrationPropertyName: map[string]interface{}{
+		"type":   TableCalibration,
+		"points": []interface{}{[]interface{}{0, 0}, []interface{}{1023, 100.0}},
+		"unit":   "%",
+	}}
+	rotary := newTestDeviceConfig("rotary", GrovePiRotarySensorDriverName, "A1")
+	rotary.Properties = map[string]interface{}{CalibrationPropertyName: "degrees"}
+	p := newTestPlatform(t, light, rotary)
+
+	if len(p.pipelines) != 2 {
+		t.Fatalf("expected 2 pipelines, got %d", len(p.pipelines))
+	}
+
+	if err := p.RemoveDevice("rotary"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := p.pipelines["rotary"]; found {
+		t.Error("pipeline of removed device should be dropped")
+	}
+
+	sound := newTestDeviceConfig("sound", GrovePiSoundSensorDriverName, "A2")
+	sound.Properties = map[string]interface{}{CalibrationPropertyName: "decibels"}
+	if _, err := p.AddDevice(sound); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected unknown preset error, got %v", err)
+	}
+}
+
+func TestGrovePiCustomSensorProfile(t *testing.T) {
+	max := 100.0
+	err := setProfiles(map[string]*config.SensorProfileConfig{
+		"tank": {Calibration: "percent", Unit: "% full", Max: &max, Levels: map[string]float64{"low": 0, "ok": 20}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setProfiles(nil)
+
+	tank := newTestDeviceConfig("tank", GrovePiAnalogSensorDriverName, "A0")
+	tank.Properties = map[string]interface{}{ProfilePropertyName: "tank"}
+	uv := newTestDeviceConfig("uv", GrovePiAnalogSensorDriverName, "A1")
+	uv.Properties = map[string]interface{}{ProfilePropertyName: "uv"}
+	p := newTestPlatform(t, tank, uv)
+
+	d, err := p.AnalogSensor("tank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile := d.Profile(); profile.Calibration.Unit != "% full" || profile.Level(50) != "ok" {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	unknown := newTestDeviceConfig("gas", GrovePiAnalogSensorDriverName, "A2")
+	unknown.Properties = map[string]interface{}{ProfilePropertyName: "mq135"}
+	if _, err := p.AddDevice(unknown); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestGrovePiFiltersProperty(t *testing.T) {
+	us := newTestDeviceConfig("distance", GrovePiUltrasonicRangerDriverName, "D4")
+	us.Properties = map[string]interface{}{FiltersPropertyName: []interface{}{
+		map[string]interface{}{"type": MedianFilter, "size": 5},
+		map[string]interface{}{"type": DeadbandFilter, "band": 2},
+	}}
+	light := newTestDeviceConfig("light", GrovePiLightSensorDriverName, "A0")
+	light.Properties = map[string]interface{}{FiltersPropertyName: []interface{}{
+		map[string]interface{}{"type": ExponentialFilter, "alpha": 0.5},
+	}}
+	p := newTestPlatform(t, us, light)
+
+	invalid := newTestDeviceConfig("sound", GrovePiSoundSensorDriverName, "A1")
+	invalid.Properties = map[string]interface{}{FiltersPropertyName: []interface{}{
+		map[string]interface{}{"type": "kalman"},
+	}}
+	if _, err := p.AddDevice(invalid); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected unknown filter error, got %v", err)
+	}
+
+	led := newTestDeviceConfig("led", GrovePiLEDDriverName, "D3")
+	led.Properties = map[string]interface{}{FiltersPropertyName: []interface{}{}}
+	if _, err := p.AddDevice(led); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected unsupported filters error, got %v", err)
+	}
+}
+
+func TestGrovePiDHTFiltersProperty(t *testing.T) {
+	dht := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+	dht.Properties = map[string]interface{}{FiltersPropertyName: []interface{}{
+		map[string]interface{}{"type": DeadbandFilter, "band": 0.5},
+	}}
+	temperature, humidity, err := dhtFiltersProperty(dht)
+	if err != nil || len(temperature) != 1 || len(humidity) != 1 || temperature[0] == humidity[0] {
+		t.Errorf("expected separate chains from the list, got %v, %v, %v", temperature, humidity, err)
+	}
+
+	dht.Properties[FiltersPropertyName] = map[string]interface{}{
+		"temperature": []interface{}{map[string]interface{}{"type": DeadbandFilter, "band": 0.2}},
+		"humidity": []interface{}{
+			map[string]interface{}{"type": MedianFilter, "size": 3},
+			map[string]interface{}{"type": DeadbandFilter, "band": 1},
+		},
+	}
+	temperature, humidity, err = dhtFiltersProperty(dht)
+	if err != nil || len(temperature) != 1 || len(humidity) != 2 {
+		t.Errorf("expected chains by the mapping, got %v, %v, %v", temperature, humidity, err)
+	}
+	newTestPlatform(t, dht)
+
+	for _, filters := range []interface{}{
+		map[string]interface{}{"humidity": []interface{}{map[string]interface{}{"type": "kalman"}}},
+		map[string]interface{}{"pressure": []interface{}{}},
+		map[string]interface{}{"humidity": "median"},
+	} {
+		invalid := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+		invalid.Properties = map[string]interface{}{FiltersPropertyName: filters}
+		if _, _, err := dhtFiltersProperty(invalid); !errors.Is(err, ErrorInvalidProperty) {
+			t.Errorf("expected invalid filters error for %v, got %v", filters, err)
+		}
+	}
+}
+
+func TestGrovePiThresholdsProperty(t *testing.T) {
+	dht := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+	dht.Properties = map[string]interface{}{ThresholdsPropertyName: map[string]interface{}{
+		"temperature": map[string]interface{}{
+			"high": map[string]interface{}{"above": 30, "hysteresis": 0.5, "minDuration": "1m"},
+			"low":  map[string]interface{}{"below": 25},
+		},
+	}}
+	p := newTestPlatform(t, dht)
+
+	thresholds := p.monitors["dht"].Thresholds()
+	if len(thresholds) != 2 || thresholds[0].Name != "high" || thresholds[0].MinDuration != time.Minute || !thresholds[1].Below {
+		t.Errorf("unexpected thresholds %+v", thresholds)
+	}
+	if err := p.AcknowledgeAlarm("dht", "high"); !errors.Is(err, driver.ErrorAlarmNotFound) {
+		t.Errorf("expected alarm not found, got %v", err)
+	}
+
+	shorthand := newTestDeviceConfig("dht2", GrovePiDHTSensorDriverName, "D8")
+	shorthand.Properties = map[string]interface{}{ThresholdsPropertyName: map[string]interface{}{
+		"temperature": map[string]interface{}{"high": 30, "low": 25.5},
+	}}
+	if _, err := p.AddDevice(shorthand); err != nil {
+		t.Fatalf("failed to add device with shorthand thresholds: %v", err)
+	}
+	thresholds = p.monitors["dht2"].Thresholds()
+	if len(thresholds) != 2 || thresholds[0].Level != 30 || thresholds[0].Below || thresholds[1].Level != 25.5 || !thresholds[1].Below {
+		t.Errorf("unexpected shorthand thresholds %+v", thresholds)
+	}
+
+	scalar := newTestDeviceConfig("sound", GrovePiSoundSensorDriverName, "A1")
+	scalar.Properties = map[string]interface{}{ThresholdsPropertyName: map[string]interface{}{
+		"data": map[string]interface{}{"loud": 500},
+	}}
+	if _, err := p.AddDevice(scalar); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected invalid shorthand threshold error, got %v", err)
+	}
+
+	invalid := newTestDeviceConfig("light", GrovePiLightSensorDriverName, "A0")
+	invalid.Properties = map[string]interface{}{ThresholdsPropertyName: map[string]interface{}{
+		"data": map[string]interface{}{"dark": map[string]interface{}{"above": 10, "below": 5}},
+	}}
+	if _, err := p.AddDevice(invalid); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected invalid threshold error, got %v", err)
+	}
+
+	if err := p.RemoveDevice("dht"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := p.monitors["dht"]; found {
+		t.Error("monitor of removed device should be dropped")
+	}
+}
+
+func TestGrovePiDHTModuleType(t *testing.T) {
+	p := newTestPlatform(t)
+	for _, c := range []struct {
+		value interface{}
+		valid bool
+	}{{"DHT22", true}, {"am2302", true}, {1, true}, {"DHT33", false}, {7, false}, {256, false}, {-1, false}} {
+		dht := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+		dht.Properties = map[string]interface{}{TypePropertyName: c.value}
+		_, err := p.AddDevice(dht)
+		if c.valid && err != nil {
+			t.Errorf("%v: unexpected error %v", c.value, err)
+		}
+		if !c.valid && !errors.Is(err, ErrorInvalidProperty) {
+			t.Errorf("%v: expected invalid property, got %v", c.value, err)
+		}
+		_ = p.RemoveDevice("dht")
+	}
+}
+
+func TestGrovePiPublishProperty(t *testing.T) {
+	us := newTestDeviceConfig("distance", GrovePiUltrasonicRangerDriverName, "D4")
+	us.Properties = map[string]interface{}{PublishPropertyName: driver.PublishAlways}
+	dht := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+	dht.Properties = map[string]interface{}{PublishPropertyName: map[string]interface{}{
+		"mode": driver.PublishOnChangeOrHeartbeat, "epsilon": 0.2, "heartbeat": "5m",
+	}}
+	p := newTestPlatform(t, us, dht)
+
+	for _, c := range []struct {
+		driver string
+		value  interface{}
+	}{
+		{GrovePiUltrasonicRangerDriverName, "sometimes"},
+		{GrovePiUltrasonicRangerDriverName, map[string]interface{}{"mode": driver.PublishHeartbeat}},
+		{GrovePiLightSensorDriverName, driver.PublishAlways},
+	} {
+		cfg := newTestDeviceConfig("invalid", c.driver, "A0")
+		cfg.Properties = map[string]interface{}{PublishPropertyName: c.value}
+		if _, err := p.AddDevice(cfg); !errors.Is(err, ErrorInvalidProperty) {
+			t.Errorf("%s %v: expected invalid property, got %v", c.driver, c.value, err)
+		}
+	}
+}
+
+func TestGrovePiUltrasonicTemperatureSource(t *testing.T) {
+	dht := newTestDeviceConfig("dht", GrovePiDHTSensorDriverName, "D7")
+	us := newTestDeviceConfig("distance", GrovePiUltrasonicRangerDriverName, "D4")
+	us.Properties = map[string]interface{}{
+		TemperaturePropertyName: "dht",
+		UnitPropertyName:        driver.Inches,
+		MaxPropertyName:         150,
+	}
+	newTestPlatform(t, dht, us)
+
+	for _, properties := range []map[string]interface{}{
+		{TemperaturePropertyName: "thermometer"},
+		{UnitPropertyName: "ft"},
+		{MinPropertyName: 10, MaxPropertyName: 5},
+	} {
+		invalid := newTestDeviceConfig("distance", GrovePiUltrasonicRangerDriverName, "D4")
+		invalid.Properties = properties
+		p := newGrovePi("test", &Master{})
+		conf := config.NewGrovePiConfig(config.WithGrovePiBus(1), config.WithGrovePiAddress(4))
+		conf.Devices = []*config.DeviceConfig{dht, invalid}
+		if err := p.Init(conf); !errors.Is(err, ErrorInvalidProperty) {
+			t.Errorf("%v: expected invalid property, got %v", properties, err)
+		}
+	}
+}
+
+func TestGrovePiGesturesProperty(t *testing.T) {
+	button := newTestDeviceConfig("button", GrovePiButtonDriverName, "D2")
+	plain := newTestDeviceConfig("plain", GrovePiButtonDriverName, "D3")
+	plain.Properties = map[string]interface{}{GesturesPropertyName: false}
+	pir := newTestDeviceConfig("pir", GrovePiPIRMotionDriverName, "D4")
+	pir.Properties = map[string]interface{}{GesturesPropertyName: map[string]interface{}{
+		"press": driver.PIRMotion, "release": driver.PIRNoMotion, "longPress": "10s", "repeat": "0s",
+	}}
+	p := newTestPlatform(t, button, plain, pir)
+
+	if g, found := p.gestures["button"]; !found || g.Timings() != driver.DefaultGestureTimings {
+		t.Error("button should recognize gestures with default timings")
+	}
+	if _, found := p.gestures["plain"]; found {
+		t.Error("gestures of plain button should be disabled")
+	}
+	if g, found := p.gestures["pir"]; !found || g.Timings().LongPress != 10*time.Second {
+		t.Error("PIR should recognize configured gestures")
+	}
+
+	invalid := newTestDeviceConfig("invalid", GrovePiButtonDriverName, "D5")
+	invalid.Properties = map[string]interface{}{GesturesPropertyName: map[string]interface{}{"longPress": "soon"}}
+	if _, err := p.AddDevice(invalid); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected invalid property, got %v", err)
+	}
+}
+
+func TestGrovePiBuzzerMelodies(t *testing.T) {
+	buzzer := newTestDeviceConfig("buzzer", GrovePiBuzzerDriverName, "D8")
+	buzzer.Properties = map[string]interface{}{MelodiesPropertyName: map[string]interface{}{
+		"doorbell": "Doorbell:d=4,o=5,b=140:8e6,8c6,4g5",
+		"beep":     "8c7,8p,8c7",
+	}}
+	p := newTestPlatform(t, buzzer)
+
+	d, err := p.Buzzer("buzzer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := d.Melodies(); len(names) != 2 || names[0] != "beep" || names[1] != "doorbell" {
+		t.Errorf("unexpected melodies %v", names)
+	}
+
+	invalid := newTestDeviceConfig("invalid", GrovePiBuzzerDriverName, "D5")
+	invalid.Properties = map[string]interface{}{MelodiesPropertyName: map[string]interface{}{"bad": "8x9"}}
+	if _, err := p.AddDevice(invalid); !errors.Is(err, ErrorInvalidProperty) {
+		t.Errorf("expected invalid melody, got %v", err)
+	}
+}
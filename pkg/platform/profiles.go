@@ -0,0 +1,94 @@
+package platform
+
+import (
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"sync"
+)
+
+var (
+	profilesMutex  sync.Mutex
+	customProfiles = map[string]*config.SensorProfileConfig{}
+)
+
+// setProfiles validates the custom sensor profiles and replaces the current ones,
+// devices created afterwards use the new profiles
+func setProfiles(profiles map[string]*config.SensorProfileConfig) error {
+	for name, pc := range profiles {
+		if _, err := newSensorProfile(name, pc); err != nil {
+			return err
+		}
+	}
+
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+
+	customProfiles = make(map[string]*config.SensorProfileConfig, len(profiles))
+	for name, pc := range profiles {
+		customProfiles[name] = pc
+	}
+	return nil
+}
+
+// profiles returns the current custom sensor profiles
+func profiles() map[string]*config.SensorProfileConfig {
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+
+	return customProfiles
+}
+
+// sensorProfile returns the custom or built-in profile, custom profiles override built-in ones
+func sensorProfile(name string) (*driver.SensorProfile, error) {
+	profilesMutex.Lock()
+	pc, found := customProfiles[name]
+	profilesMutex.Unlock()
+
+	if found {
+		return newSensorProfile(name, pc)
+	}
+	if profile, found := driver.BuiltinSensorProfile(name); found {
+		return profile, nil
+	}
+	return nil, fmt.Errorf("%w: unknown sensor profile %q", ErrorInvalidProperty, name)
+}
+
+func newSensorProfile(name string, pc *config.SensorProfileConfig) (*driver.SensorProfile, error) {
+	if pc == nil {
+		return nil, fmt.Errorf("%w: sensor profile %s is empty", ErrorInvalidProperty, name)
+	}
+
+	var c *driver.Calibration
+	if pc.Calibration != nil {
+		var err error
+		if c, err = parseCalibration(pc.Calibration); err != nil {
+			return nil, fmt.Errorf("%w: calibration of sensor profile %s: %v", ErrorInvalidProperty, name, err)
+		}
+	} else {
+		c = driver.NewLinearCalibration(1, 0, "")
+	}
+	if pc.Unit != "" {
+		c.Unit = pc.Unit
+	}
+
+	var r *driver.Range
+	if pc.Min != nil || pc.Max != nil {
+		r = &driver.Range{Min: -1e308, Max: 1e308}
+		if pc.Min != nil {
+			r.Min = *pc.Min
+		}
+		if pc.Max != nil {
+			r.Max = *pc.Max
+		}
+		if r.Max < r.Min {
+			return nil, fmt.Errorf("%w: sensor profile %s has empty range", ErrorInvalidProperty, name)
+		}
+	}
+
+	levels := make([]driver.ProfileLevel, 0, len(pc.Levels))
+	for level, from := range pc.Levels {
+		levels = append(levels, driver.ProfileLevel{Name: level, From: from})
+	}
+	return driver.NewSensorProfile(c, r, levels...), nil
+}
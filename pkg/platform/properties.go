@@ -0,0 +1,105 @@
+package platform
+
+import (
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	"time"
+)
+
+// driverProperties are the properties supported by some drivers only
+var driverProperties = map[string]map[string]bool{
+	FiltersPropertyName: filteredDrivers,
+	PublishPropertyName: policyDrivers,
+}
+
+// intProperty returns integer device property or the default when the property is missing
+func intProperty(cfg *config.DeviceConfig, name string, def int) (int, error) {
+	switch v := cfg.Properties[name].(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case nil:
+		return def, nil
+	default:
+		return 0, fmt.Errorf("%w: %s of %s is %T, not an integer", ErrorInvalidProperty, name, cfg.Name, v)
+	}
+}
+
+// floatProperty returns numeric device property or the default when the property is missing
+func floatProperty(cfg *config.DeviceConfig, name string, def float64) (float64, error) {
+	switch v := cfg.Properties[name].(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case nil:
+		return def, nil
+	default:
+		return 0, fmt.Errorf("%w: %s of %s is %T, not a number", ErrorInvalidProperty, name, cfg.Name, v)
+	}
+}
+
+// boolProperty returns boolean device property or the default when the property is missing
+func boolProperty(cfg *config.DeviceConfig, name string, def bool) (bool, error) {
+	switch v := cfg.Properties[name].(type) {
+	case bool:
+		return v, nil
+	case nil:
+		return def, nil
+	default:
+		return false, fmt.Errorf("%w: %s of %s is %T, not a boolean", ErrorInvalidProperty, name, cfg.Name, v)
+	}
+}
+
+// stringProperty returns string device property or the default when the property is missing
+func stringProperty(cfg *config.DeviceConfig, name string, def string) (string, error) {
+	switch v := cfg.Properties[name].(type) {
+	case string:
+		return v, nil
+	case nil:
+		return def, nil
+	default:
+		return "", fmt.Errorf("%w: %s of %s is %T, not a string", ErrorInvalidProperty, name, cfg.Name, v)
+	}
+}
+
+// durationProperty returns device property in time.ParseDuration format or the default when the property is missing
+func durationProperty(cfg *config.DeviceConfig, name string, def time.Duration) (time.Duration, error) {
+	s, err := stringProperty(cfg, name, "")
+	if err != nil || s == "" {
+		return def, err
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, name, cfg.Name, err)
+	}
+	return d, nil
+}
+
+// mapProperty returns mapping device property, non-string keys parsed from YAML are converted to strings
+func mapProperty(cfg *config.DeviceConfig, name string) (map[string]interface{}, error) {
+	v := cfg.Properties[name]
+	if v == nil {
+		return map[string]interface{}{}, nil
+	}
+	if m, ok := stringMap(v); ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("%w: %s of %s is %T, not a mapping", ErrorInvalidProperty, name, cfg.Name, v)
+}
+
+// stringMap returns mapping decoded from JSON or YAML, non-string keys are converted to strings
+func stringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, value := range m {
+			converted[fmt.Sprint(k)] = value
+		}
+		return converted, true
+	}
+	return nil, false
+}
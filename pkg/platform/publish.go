@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"time"
+)
+
+// policyDrivers are the polling drivers supporting the publish property
+var policyDrivers = map[string]bool{
+	GrovePiUltrasonicRangerDriverName: true,
+	GrovePiDHTSensorDriverName:        true,
+}
+
+// publishPolicy is implemented by the drivers supporting the publish property
+type publishPolicy interface {
+	SetPublishPolicy(p driver.PublishPolicy) error
+}
+
+// setPublishPolicy applies the publish policy which is either a mode name or a mapping, e.g.
+//   publish: always
+//   publish: {mode: onChange, epsilon: 0.5}
+//   publish: {mode: onChangeOrHeartbeat, epsilon: 0.5, heartbeat: 5m}
+func setPublishPolicy(d publishPolicy, cfg *config.DeviceConfig) error {
+	v, found := cfg.Properties[PublishPropertyName]
+	if !found {
+		return nil
+	}
+
+	p := driver.DefaultPublishPolicy
+	if mode, ok := v.(string); ok {
+		p.Mode = mode
+	} else if m, ok := stringMap(v); ok {
+		if p.Mode, ok = valueOr(m["mode"], p.Mode).(string); !ok {
+			return fmt.Errorf("%w: %s mode of %s should be a string", ErrorInvalidProperty, PublishPropertyName, cfg.Name)
+		}
+		if p.Epsilon, ok = number(valueOr(m["epsilon"], 0.0)); !ok {
+			return fmt.Errorf("%w: %s epsilon of %s should be a number", ErrorInvalidProperty, PublishPropertyName, cfg.Name)
+		}
+		if s, found := m["heartbeat"].(string); found {
+			heartbeat, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("%w: %s heartbeat of %s: %v", ErrorInvalidProperty, PublishPropertyName, cfg.Name, err)
+			}
+			p.Heartbeat = heartbeat
+		}
+	} else {
+		return fmt.Errorf("%w: %s of %s is %T, neither mode nor mapping", ErrorInvalidProperty, PublishPropertyName, cfg.Name, v)
+	}
+
+	if err := d.SetPublishPolicy(p); err != nil {
+		return fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, PublishPropertyName, cfg.Name, err)
+	}
+	return nil
+}
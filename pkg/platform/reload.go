@@ -0,0 +1,212 @@
+package platform
+
+import (
+	"gobot-grovepi-platform/pkg/config"
+	"gobot.io/x/gobot"
+	"gobot.io/x/gobot/drivers/i2c"
+	"reflect"
+)
+
+// Reload applies the new device configuration without restarting the platform.
+// Removed devices are halted, added ones are started and only the devices whose driver, pin or properties
+// changed are restarted. When the new configuration is invalid or a device fails to start the running
+// devices are left as they were
+func (p *GrovePi) Reload(conf *config.GrovePiConfig) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	r, err := p.prepareReload(conf)
+	if err != nil {
+		return err
+	}
+	if err := r.swap(); err != nil {
+		r.rollback()
+		return err
+	}
+	r.commit()
+	return nil
+}
+
+// reload is the validated device configuration of the platform which isn't applied yet,
+// so reloads of several platforms can be applied or rolled back together
+type reload struct {
+	p       *GrovePi
+	conf    *config.GrovePiConfig
+	options []func(i2c.Config)
+	next    *GrovePi
+	stale   []gobot.Device
+	created []gobot.Device
+	swapped bool
+}
+
+// prepareReload creates and binds the new devices, the running devices are left untouched.
+// The caller must hold the platform mutex
+func (p *GrovePi) prepareReload(conf *config.GrovePiConfig) (*reload, error) {
+	if p.robot == nil {
+		return nil, ErrorNotInitialized
+	}
+	if conf.Bus != p.bus || grovePiAddress(conf) != p.address {
+		return nil, ErrorReloadNotSupported
+	}
+
+	options, err := grovePiOptions(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]*config.DeviceConfig{}
+	for _, cfg := range conf.Devices {
+		if _, duplicate := wanted[cfg.Name]; duplicate {
+			return nil, ErrorNameAlreadyInUse
+		}
+		wanted[cfg.Name] = cfg
+	}
+
+	next := newGrovePi(p.name, p.master)
+	next.adaptor = p.adaptor
+
+	stale := make([]gobot.Device, 0)
+	for name, d := range p.devicesByName {
+		cfg, found := wanted[name]
+		if found && sameDeviceConfig(cfg, p.deviceConfigs[name]) {
+			next.devicesByName[name] = d
+			next.devicesByPin[cfg.Pin] = d
+			next.deviceConfigs[name] = cfg
+			if pipeline, found := p.pipelines[name]; found {
+				next.pipelines[name] = pipeline
+			}
+			if monitor, found := p.monitors[name]; found {
+				next.monitors[name] = monitor
+			}
+			if gestures, found := p.gestures[name]; found {
+				next.gestures[name] = gestures
+			}
+			continue
+		}
+		stale = append(stale, d)
+	}
+
+	added := make([]*config.DeviceConfig, 0)
+	for _, cfg := range conf.Devices {
+		if _, kept := next.devicesByName[cfg.Name]; !kept {
+			added = append(added, cfg)
+		}
+	}
+
+	created, err := next.createDevices(p.grovepi, added...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bindDevices(next.devicesByName, next.deviceConfigs); err != nil {
+		_ = bindDevices(p.devicesByName, p.deviceConfigs)
+		return nil, err
+	}
+	return &reload{p: p, conf: conf, options: options, next: next, stale: stale, created: created}, nil
+}
+
+// swap halts the stale devices and starts the created ones of the running platform
+func (r *reload) swap() error {
+	if !r.p.robot.Running() {
+		return nil
+	}
+	if err := swapDevices(r.stale, r.created); err != nil {
+		return err
+	}
+	r.swapped = true
+	return nil
+}
+
+// rollback restores the running devices and their bindings
+func (r *reload) rollback() {
+	if r.swapped {
+		haltDevices(r.created)
+		startDevices(r.stale)
+		r.swapped = false
+	}
+	_ = bindDevices(r.p.devicesByName, r.p.deviceConfigs)
+}
+
+// commit replaces the platform devices and config with the reloaded ones
+func (r *reload) commit() {
+	p, next := r.p, r.next
+
+	p.grovepi.ClearRetryPolicies()
+	for _, option := range r.options {
+		option(p.grovepi)
+	}
+
+	p.devicesByName = next.devicesByName
+	p.devicesByPin = next.devicesByPin
+	p.deviceConfigs = next.deviceConfigs
+	stalePipelines := p.pipelines
+	p.pipelines = next.pipelines
+	p.attachPipelines(stalePipelines)
+	staleMonitors := p.monitors
+	p.monitors = next.monitors
+	p.attachMonitors(staleMonitors)
+	staleGestures := p.gestures
+	p.gestures = next.gestures
+	p.attachGestures(staleGestures)
+	p.conf = r.conf
+	p.updateRobotDevices()
+}
+
+// updateRobotDevices replaces robot devices with the GrovePi driver followed by the configured devices
+func (p *GrovePi) updateRobotDevices() {
+	ds := gobot.Devices{p.grovepi}
+	for _, cfg := range p.conf.Devices {
+		if d, found := p.devicesByName[cfg.Name]; found {
+			ds = append(ds, d)
+		}
+	}
+	*p.robot.Devices() = ds
+}
+
+// swapDevices halts stale devices and starts created ones, on failure the stale devices are started again
+func swapDevices(stale, created []gobot.Device) error {
+	halted := make([]gobot.Device, 0, len(stale))
+	for _, d := range stale {
+		if err := d.Halt(); err != nil {
+			startDevices(halted)
+			return err
+		}
+		halted = append(halted, d)
+	}
+
+	started := make([]gobot.Device, 0, len(created))
+	for _, d := range created {
+		if err := d.Start(); err != nil {
+			haltDevices(started)
+			startDevices(halted)
+			return err
+		}
+		started = append(started, d)
+	}
+	return nil
+}
+
+func startDevices(devices []gobot.Device) {
+	for _, d := range devices {
+		_ = d.Start()
+	}
+}
+
+func haltDevices(devices []gobot.Device) {
+	for _, d := range devices {
+		_ = d.Halt()
+	}
+}
+
+func sameDeviceConfig(a, b *config.DeviceConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Driver != b.Driver || a.Pin != b.Pin {
+		return false
+	}
+	if len(a.Properties) == 0 && len(b.Properties) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Properties, b.Properties)
+}
@@ -0,0 +1,165 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"gobot-grovepi-platform/pkg/config"
+	driver "gobot-grovepi-platform/pkg/gobot-driver"
+	"gobot.io/x/gobot"
+	"sort"
+	"time"
+)
+
+// Names of the thresholds which can be set by their level only, high is an upper limit and low a lower one
+const (
+	highThreshold = "high"
+	lowThreshold  = "low"
+)
+
+// newMonitor creates the threshold monitor of the device, nil when the device config has no thresholds
+func newMonitor(d gobot.Device, cfg *config.DeviceConfig) (*driver.Monitor, error) {
+	if _, found := cfg.Properties[ThresholdsPropertyName]; !found {
+		return nil, nil
+	}
+	if _, ok := d.(gobot.Eventer); !ok {
+		return nil, fmt.Errorf("%w: %s doesn't publish values", ErrorInvalidProperty, cfg.Name)
+	}
+
+	thresholds, err := thresholdsProperty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m, err := driver.NewMonitor(thresholds...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s of %s: %v", ErrorInvalidProperty, ThresholdsPropertyName, cfg.Name, err)
+	}
+	return m, nil
+}
+
+// thresholdsProperty parses the named thresholds grouped by the device event, e.g.
+//   thresholds:
+//     temperature:
+//       high: {above: 30, hysteresis: 0.5, minDuration: 1m}
+//       low: {below: 25}
+// the high and low thresholds can be shortened to their level, e.g. "temperature: {high: 30, low: 25}"
+func thresholdsProperty(cfg *config.DeviceConfig) ([]driver.Threshold, error) {
+	events, err := mapProperty(cfg, ThresholdsPropertyName)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := make([]driver.Threshold, 0)
+	for _, event := range sortedKeys(events) {
+		named, ok := stringMap(events[event])
+		if !ok {
+			return nil, fmt.Errorf("%w: %s of %s should map %s thresholds by name", ErrorInvalidProperty, ThresholdsPropertyName, cfg.Name, event)
+		}
+		for _, name := range sortedKeys(named) {
+			t, err := parseThreshold(event, name, named[name])
+			if err != nil {
+				return nil, fmt.Errorf("%w: threshold %s of %s: %v", ErrorInvalidProperty, name, cfg.Name, err)
+			}
+			thresholds = append(thresholds, t)
+		}
+	}
+	return thresholds, nil
+}
+
+// attachMonitors attaches the threshold monitors to their devices and detaches the old ones which aren't used anymore,
+// the caller must hold the mutex
+func (p *GrovePi) attachMonitors(old map[string]*driver.Monitor) {
+	for name, monitor := range p.monitors {
+		if source, ok := p.devicesByName[name].(gobot.Eventer); ok {
+			monitor.Attach(source)
+		}
+	}
+	for name, monitor := range old {
+		if p.monitors[name] != monitor {
+			monitor.Detach()
+		}
+	}
+}
+
+func parseThreshold(event, name string, v interface{}) (driver.Threshold, error) {
+	t := driver.Threshold{Name: name, Event: event}
+	if level, ok := number(v); ok {
+		switch name {
+		case highThreshold:
+			t.Level = level
+		case lowThreshold:
+			t.Level, t.Below = level, true
+		default:
+			return t, fmt.Errorf("only %s and %s thresholds can be set by the level, others need above or below", highThreshold, lowThreshold)
+		}
+		return t, nil
+	}
+	m, ok := stringMap(v)
+	if !ok {
+		return t, fmt.Errorf("%T isn't a mapping", v)
+	}
+
+	above, isAbove := number(m["above"])
+	below, isBelow := number(m["below"])
+	switch {
+	case isAbove == isBelow:
+		return t, errors.New("either above or below level should be set")
+	case isAbove:
+		t.Level = above
+	default:
+		t.Level, t.Below = below, true
+	}
+
+	if t.Hysteresis, ok = number(valueOr(m["hysteresis"], 0.0)); !ok {
+		return t, errors.New("hysteresis should be a number")
+	}
+	if s, found := m["minDuration"]; found {
+		str, ok := s.(string)
+		if !ok {
+			return t, errors.New("minDuration should be a duration")
+		}
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return t, err
+		}
+		t.MinDuration = d
+	}
+	return t, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Alarms returns the raised alarms of the platform devices
+func (p *GrovePi) Alarms() []driver.Alarm {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	alarms := make([]driver.Alarm, 0)
+	if p.conf == nil {
+		return alarms
+	}
+	for _, cfg := range p.conf.Devices {
+		if m, found := p.monitors[cfg.Name]; found {
+			alarms = append(alarms, m.Alarms()...)
+		}
+	}
+	return alarms
+}
+
+// AcknowledgeAlarm acknowledges the raised alarm of the device threshold
+func (p *GrovePi) AcknowledgeAlarm(device, threshold string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	m, found := p.monitors[device]
+	if !found {
+		return fmt.Errorf("%w: %s", driver.ErrorAlarmNotFound, threshold)
+	}
+	return m.Acknowledge(threshold)
+}